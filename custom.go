@@ -0,0 +1,42 @@
+package structvalidator
+
+import (
+	"reflect"
+	"sync"
+)
+
+var customValidators = map[string]func(reflect.Value) bool{}
+var customValidatorsMu sync.RWMutex
+
+// RegisterValidator adds validator that can be used in tags with "custom:name" token, eg.
+// `validation:"req custom:nospaces"`. Field fails with FailCustom when fn returns false. Registering validator with
+// the same name again replaces it. Custom names that are not registered are ignored.
+func RegisterValidator(name string, fn func(value reflect.Value) bool) {
+	customValidatorsMu.Lock()
+	defer customValidatorsMu.Unlock()
+	customValidators[name] = fn
+}
+
+// customRule returns rule that fails with FailCustom when validator fn returns false.
+func customRule(fn func(reflect.Value) bool) func(reflect.Value) (bool, int) {
+	return func(value reflect.Value) (bool, int) {
+		if !fn(value) {
+			return false, FailCustom
+		}
+		return true, 0
+	}
+}
+
+// customValidator returns validator with name from ValidationOptions.CustomValidators or, when it is not there, from
+// validators added with RegisterValidator.
+func customValidator(name string, options *ValidationOptions) (func(reflect.Value) bool, bool) {
+	if options != nil {
+		if fn, ok := options.CustomValidators[name]; ok {
+			return fn, true
+		}
+	}
+	customValidatorsMu.RLock()
+	defer customValidatorsMu.RUnlock()
+	fn, ok := customValidators[name]
+	return fn, ok
+}
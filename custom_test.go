@@ -0,0 +1,48 @@
+package structvalidator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Test31 struct {
+	Username string `validation:"req custom:nocaps"`
+	Nickname string `validation:"custom:nocaps custom:nodigits"`
+	Bio      string `validation:"custom:unknown"`
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("nocaps", func(value reflect.Value) bool {
+		return strings.ToLower(value.String()) == value.String()
+	})
+
+	s := Test31{
+		Username: "john",
+		Nickname: "johnny5",
+		Bio:      "Anything",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s.Username = "John"
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailCustom,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		CustomValidators: map[string]func(reflect.Value) bool{
+			"nodigits": func(value reflect.Value) bool {
+				return !strings.ContainsAny(value.String(), "0123456789")
+			},
+			"nocaps": func(value reflect.Value) bool {
+				return true
+			},
+		},
+	}
+	expectedFailedFields = map[string]int{
+		"Nickname": FailCustom,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
@@ -0,0 +1,45 @@
+package structvalidator
+
+import (
+	"strings"
+)
+
+// ValidationError is an error returned by ValidateErr when struct has invalid fields.
+type ValidationError struct {
+	failed      map[string]int
+	fields      []string
+	validations map[string]*FieldValidation
+}
+
+// Error returns fields that failed, in the order they were validated, with names of rules they failed on, eg.
+// "validation failed: FirstName (lenmin), Age (valmin)".
+func (e *ValidationError) Error() string {
+	names := failureNames(e.failed, e.validations)
+	fields := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		fields = append(fields, field+" ("+strings.Join(names[field], ", ")+")")
+	}
+	return "validation failed: " + strings.Join(fields, ", ")
+}
+
+// Errors returns failures of invalid fields, the same as the map returned by Validate.
+func (e *ValidationError) Errors() map[string]int {
+	failed := make(map[string]int, len(e.failed))
+	for field, flags := range e.failed {
+		failed[field] = flags
+	}
+	return failed
+}
+
+// ValidateErr validates struct the same way as Validate and returns nil when it is valid and *ValidationError
+// otherwise. When obj is not a non-nil pointer to struct, the error is the one returned by ValidateSafe.
+func ValidateErr(obj interface{}, options *ValidationOptions) error {
+	result := validate(obj, options)
+	if result.inputErr != nil {
+		return result.inputErr
+	}
+	if result.valid {
+		return nil
+	}
+	return &ValidationError{failed: result.failed, fields: result.fields, validations: result.validations}
+}
@@ -0,0 +1,45 @@
+package structvalidator
+
+import (
+	"testing"
+)
+
+func TestValidateErr(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           35,
+		Price:         100,
+		PostCode:      "00-123",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 50,
+		Country:       "PL",
+	}
+	if err := ValidateErr(&s, &ValidationOptions{}); err != nil {
+		t.Fatalf("ValidateErr returned error %v for valid struct", err)
+	}
+
+	s.FirstName = "John"
+	s.LastName = ""
+	s.Age = 15
+	err := ValidateErr(&s, &ValidationOptions{})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("ValidateErr returned %#v where it should be *ValidationError", err)
+	}
+	compareFailedFields(validationErr.Errors(), map[string]int{
+		"FirstName": FailLenMin,
+		"LastName":  FailEmpty | FailLenMin,
+		"Age":       FailValMin,
+	}, t)
+	expectedMessage := "validation failed: FirstName (lenmin), LastName (req, lenmin), Age (valmin)"
+	if err.Error() != expectedMessage {
+		t.Fatalf("ValidationError returned message '%s' where it should be '%s'", err.Error(), expectedMessage)
+	}
+
+	err = ValidateErr(nil, &ValidationOptions{})
+	if _, ok := err.(*ValidationError); err == nil || ok {
+		t.Fatalf("ValidateErr returned invalid error %#v for nil", err)
+	}
+}
@@ -0,0 +1,250 @@
+package structvalidator
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ResultFormat defines the shape of failures returned by ValidateFormatted
+type ResultFormat int
+
+// values for ResultFormat
+const (
+	ResultFlags ResultFormat = iota
+	ResultNames
+	ResultMessages
+)
+
+// failures maps Fail* constants to rule names and default message templates. Order of the slice defines order in
+// which names and messages are returned when field failed on more than one rule. Rules that share a flag have
+// rule func telling whether field has the rule; the first of them describes the flag when field has none of them.
+var failures = []struct {
+	flag    int
+	name    string
+	message string
+	rule    func(v *FieldValidation) bool
+}{
+	{FailEmpty, "req", "{field} is required", func(v *FieldValidation) bool { return v.flags&Required > 0 }},
+	{FailZero, "req", "{field} is required", nil},
+	{FailBlank, "notblank", "{field} must not be blank", func(v *FieldValidation) bool { return v.flags&NotBlank > 0 }},
+	{FailLenMin, "lenmin", "{field} is shorter than {min} characters", func(v *FieldValidation) bool { return v.lenMin > 0 }},
+	{FailLenMax, "lenmax", "{field} is longer than {max} characters", func(v *FieldValidation) bool { return v.lenMax > 0 }},
+	{FailLen, "len", "{field} has invalid length", func(v *FieldValidation) bool { return v.length > -1 }},
+	{FailByteMin, "bytemin", "{field} is shorter than {min} bytes", func(v *FieldValidation) bool { return v.byteMin > 0 }},
+	{FailByteMax, "bytemax", "{field} is longer than {max} bytes", func(v *FieldValidation) bool { return v.byteMax > 0 }},
+	{FailLenIn, "lenin", "{field} has length that is not allowed", func(v *FieldValidation) bool { return len(v.lenIn) > 0 }},
+	{FailValMin, "valmin", "{field} is less than {min}", func(v *FieldValidation) bool { return v.valMin != 0 || v.flags&ValMinNotNil > 0 }},
+	{FailValMax, "valmax", "{field} is greater than {max}", func(v *FieldValidation) bool { return v.valMax != 0 || v.flags&ValMaxNotNil > 0 }},
+	{FailGt, "gt", "{field} must be greater than {min}", func(v *FieldValidation) bool { return v.gt != nil }},
+	{FailGte, "gte", "{field} must be greater than or equal to {min}", func(v *FieldValidation) bool { return v.gte != nil }},
+	{FailLt, "lt", "{field} must be less than {max}", func(v *FieldValidation) bool { return v.lt != nil }},
+	{FailLte, "lte", "{field} must be less than or equal to {max}", func(v *FieldValidation) bool { return v.lte != nil }},
+	{FailNotNumeric, "notnumeric", "{field} is not a number", nil},
+	{FailValIn, "valin", "{field} has value that is not allowed", func(v *FieldValidation) bool { return len(v.valIn) > 0 }},
+	{FailOneOf, "oneof", "{field} is not one of allowed values", func(v *FieldValidation) bool { return len(v.oneOf) > 0 }},
+	{FailPowerOf, "powerof", "{field} is not a power of allowed base", func(v *FieldValidation) bool { return v.powerOf > 0 }},
+	{FailMaxDecimals, "maxdecimals", "{field} has too many decimal places", nil},
+	{FailRegexp, "regexp", "{field} has invalid format", func(v *FieldValidation) bool { return v.regexp != nil || v.invalidRegexp }},
+	{FailRegexpGroup, "regexpgroup", "{field} is missing a required part", nil},
+	{FailEmail, "email", "{field} is not a valid email address", nil},
+	{FailBool, "mustbe", "{field} has value that is not allowed", func(v *FieldValidation) bool { return v.flags&(MustBeTrue|MustBeFalse) > 0 }},
+	{FailDateTime, "datetime", "{field} is not a valid date or time", func(v *FieldValidation) bool { return v.dateTime != "" }},
+	{FailBefore, "before", "{field} is too late", func(v *FieldValidation) bool { return !v.before.IsZero() }},
+	{FailAfter, "after", "{field} is too early", func(v *FieldValidation) bool { return !v.after.IsZero() }},
+	{FailWeekday, "weekday", "{field} is on a day of week that is not allowed", func(v *FieldValidation) bool { return v.flags&Weekday > 0 || len(v.daysOfWeek) > 0 }},
+	{FailFileMode, "filemode", "{field} is not a valid file mode", func(v *FieldValidation) bool { return v.flags&FileMode > 0 }},
+	{FailElem, "elem", "{field} contains an invalid element", func(v *FieldValidation) bool { return v.elem != nil }},
+	{FailEqField, "eqfield", "{field} is not equal to another field", func(v *FieldValidation) bool { return v.eqField != "" }},
+	{FailNeField, "nefield", "{field} must be different than another field", func(v *FieldValidation) bool { return v.neField != "" }},
+	{FailMapVal, "mapval", "{field} contains an invalid value", func(v *FieldValidation) bool { return v.mapVal != nil }},
+	{FailLenEqField, "leneqfield", "{field} has length different than value of another field", func(v *FieldValidation) bool { return v.lenEqField != "" }},
+	{FailContains, "contains", "{field} does not contain a required text", func(v *FieldValidation) bool { return len(v.contains) > 0 }},
+	{FailExcludes, "excludes", "{field} contains a text that is not allowed", func(v *FieldValidation) bool { return len(v.excludes) > 0 }},
+	{FailStartsWith, "startswith", "{field} does not start with a required prefix", func(v *FieldValidation) bool { return v.startsWith != "" }},
+	{FailEndsWith, "endswith", "{field} does not end with a required suffix", func(v *FieldValidation) bool { return v.endsWith != "" }},
+	{FailURL, "url", "{field} is not a valid URL", func(v *FieldValidation) bool { return v.flags&URL > 0 }},
+	{FailUUID, "uuid", "{field} is not a valid UUID", func(v *FieldValidation) bool { return v.flags&UUID > 0 }},
+	{FailIP, "ip", "{field} is not a valid IP address", func(v *FieldValidation) bool { return v.flags&(IP|IPv4|IPv6) > 0 }},
+	{FailCIDR, "cidr", "{field} is not a valid CIDR notation", func(v *FieldValidation) bool { return v.flags&CIDR > 0 }},
+	{FailColor, "color", "{field} is not a valid color", func(v *FieldValidation) bool { return v.flags&Color > 0 }},
+	{FailPassword, "password", "{field} is not a strong enough password", func(v *FieldValidation) bool { return v.password != nil }},
+	{FailHexColor, "hexcolor", "{field} is not a valid hex color", func(v *FieldValidation) bool { return v.flags&HexColor > 0 }},
+	{FailBase64, "base64", "{field} is not valid base64", func(v *FieldValidation) bool { return v.flags&Base64 > 0 }},
+	{FailHex, "hex", "{field} is not a hexadecimal number", func(v *FieldValidation) bool { return v.flags&Hex > 0 }},
+	{FailBcrypt, "bcrypt", "{field} is not a bcrypt hash", func(v *FieldValidation) bool { return v.flags&Bcrypt > 0 }},
+	{FailCharset, "alpha", "{field} contains characters that are not allowed", func(v *FieldValidation) bool { return v.flags&Alpha > 0 }},
+	{FailCharset, "numeric", "{field} contains characters that are not allowed", func(v *FieldValidation) bool { return v.flags&Numeric > 0 }},
+	{FailCharset, "alphanumeric", "{field} contains characters that are not allowed", func(v *FieldValidation) bool { return v.flags&AlphaNumeric > 0 }},
+	{FailUnicodeClass, "unicodeclass", "{field} contains characters that are not allowed", func(v *FieldValidation) bool { return len(v.unicodeClasses) > 0 }},
+	{FailPositive, "positive", "{field} must be positive", func(v *FieldValidation) bool { return v.flags&Positive > 0 }},
+	{FailNegative, "negative", "{field} must be negative", func(v *FieldValidation) bool { return v.flags&Negative > 0 }},
+	{FailNonNegative, "nonnegative", "{field} must not be negative", func(v *FieldValidation) bool { return v.flags&NonNegative > 0 }},
+	{FailNonPositive, "nonpositive", "{field} must not be positive", func(v *FieldValidation) bool { return v.flags&NonPositive > 0 }},
+	{FailRequireAny, "requireany", "at least one of {field} is required", nil},
+	{FailMutuallyExclusive, "mutuallyexclusive", "only one of {field} can be set", nil},
+	{FailFormatField, "formatfield", "{field} has unknown format", nil},
+	{FailCustom, "custom", "{field} is invalid", nil},
+	{FailExternal, "external", "{field} is invalid", nil},
+	{FailTimeout, "timeout", "{field} took too long to validate", nil},
+	{FailBudgetExceeded, "budget", "{field} was not validated because validation took too long", nil},
+	{FailPanic, "panic", "{field} could not be validated", nil},
+	{FailOverwriteType, "overwritetype", "{field} has overwrite value of incompatible type", nil},
+}
+
+var locales = map[string]map[int]string{}
+var localesMu sync.RWMutex
+
+// RegisterLocale adds message templates, keyed by Fail* constant, that ValidateWithMessages uses when
+// ValidationOptions.Locale is set to locale. Templates can contain {field}, {min} and {max} placeholders.
+// Registering the same locale again replaces its templates.
+func RegisterLocale(locale string, templates map[int]string) {
+	localeTemplates := map[int]string{}
+	for flag, template := range templates {
+		localeTemplates[flag] = template
+	}
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[locale] = localeTemplates
+}
+
+// ValidateFormatted validates struct the same way as Validate but returns failures in the shape set with
+// ValidationOptions.ResultFormat: map[string]int (default), map[string][]string or map[string]string.
+func ValidateFormatted(obj interface{}, options *ValidationOptions) (bool, interface{}) {
+	if options == nil {
+		return Validate(obj, options)
+	}
+	switch options.ResultFormat {
+	case ResultNames:
+		return ValidateNames(obj, options)
+	case ResultMessages:
+		return ValidateWithMessages(obj, options)
+	}
+	return Validate(obj, options)
+}
+
+// ValidateNames validates struct and returns names of rules that each invalid field failed on, eg. "lenmin".
+func ValidateNames(obj interface{}, options *ValidationOptions) (bool, map[string][]string) {
+	result := validate(obj, options)
+	return result.valid, failureNames(result.failed, result.validations)
+}
+
+// ValidateMessages validates struct and returns a message describing failure of each invalid field. It is the same
+// as ValidateWithMessages.
+func ValidateMessages(obj interface{}, options *ValidationOptions) (bool, map[string]string) {
+	return ValidateWithMessages(obj, options)
+}
+
+// ValidateWithMessages validates struct and returns a human-readable message for each invalid field, eg.
+// "FirstName is shorter than 5 characters". Default messages can be overwritten with ValidationOptions.Messages.
+func ValidateWithMessages(obj interface{}, options *ValidationOptions) (bool, map[string]string) {
+	result := validate(obj, options)
+	templates := messageTemplates(options)
+	messages := map[string]string{}
+	for field, flags := range result.failed {
+		messages[field] = failureMessage(field, flags, result.validations[field], templates)
+	}
+	return result.valid, messages
+}
+
+// messageTemplates returns templates that overwrite default messages: ones from ValidationOptions.Messages and, for
+// the rest of Fail* constants, ones registered for ValidationOptions.Locale.
+func messageTemplates(options *ValidationOptions) map[int]string {
+	if options == nil {
+		return nil
+	}
+	if options.Locale == "" {
+		return options.Messages
+	}
+	templates := map[int]string{}
+	localesMu.RLock()
+	for flag, template := range locales[options.Locale] {
+		templates[flag] = template
+	}
+	localesMu.RUnlock()
+	for flag, template := range options.Messages {
+		templates[flag] = template
+	}
+	return templates
+}
+
+func failureNames(invalidFields map[string]int, validations map[string]*FieldValidation) map[string][]string {
+	names := map[string][]string{}
+	for field, flags := range invalidFields {
+		names[field] = []string{}
+		for _, f := range failedRules(flags, validations[field]) {
+			names[field] = append(names[field], failures[f].name)
+		}
+	}
+	return names
+}
+
+// failedRules returns indexes of failures entries describing flags of field with validation. Flag shared by rules
+// is described by the rules field has, or by the first of them when validation is nil or has none of them.
+func failedRules(flags int, validation *FieldValidation) []int {
+	described := 0
+	for _, f := range failures {
+		if validation != nil && f.rule != nil && f.rule(validation) {
+			described = described | f.flag
+		}
+	}
+	rules := []int{}
+	for i, f := range failures {
+		switch {
+		case flags&f.flag == 0:
+		case described&f.flag == 0:
+			rules = append(rules, i)
+			described = described | f.flag
+		case f.rule != nil && validation != nil && f.rule(validation):
+			rules = append(rules, i)
+		}
+	}
+	return rules
+}
+
+// failureMessage returns message for field that failed with flags. Messages for each flag are joined with ", ".
+// Validation can be nil (eg. for group rules) in which case bounds are left empty.
+func failureMessage(field string, flags int, validation *FieldValidation, templates map[int]string) string {
+	fieldMessages := []string{}
+	for _, i := range failedRules(flags, validation) {
+		f := failures[i]
+		template := f.message
+		if templates[f.flag] != "" {
+			template = templates[f.flag]
+		}
+		min, max := messageBounds(f.name, validation)
+		fieldMessages = append(fieldMessages, strings.NewReplacer("{field}", field, "{min}", min, "{max}", max).Replace(template))
+	}
+	return strings.Join(fieldMessages, ", ")
+}
+
+// messageBounds returns values for {min} and {max} placeholders in message for rule.
+func messageBounds(rule string, validation *FieldValidation) (string, string) {
+	if validation == nil {
+		return "", ""
+	}
+	switch rule {
+	case "lenmin", "lenmax":
+		return strconv.Itoa(validation.lenMin), strconv.Itoa(validation.lenMax)
+	case "gt":
+		return formatBound(validation.gt), ""
+	case "gte":
+		return formatBound(validation.gte), ""
+	case "lt":
+		return "", formatBound(validation.lt)
+	case "lte":
+		return "", formatBound(validation.lte)
+	case "bytemin", "bytemax":
+		return strconv.Itoa(validation.byteMin), strconv.Itoa(validation.byteMax)
+	case "valmin", "valmax":
+		return strconv.FormatInt(validation.valMin, 10), strconv.FormatInt(validation.valMax, 10)
+	}
+	return "", ""
+}
+
+func formatBound(bound *float64) string {
+	if bound == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*bound, 'f', -1, 64)
+}
@@ -0,0 +1,232 @@
+package structvalidator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateNamesAndMessages(t *testing.T) {
+	s := Test1{
+		FirstName:     "123456789012345678901234567890",
+		LastName:      "Smith",
+		Age:           15,
+		PostCode:      "43-155",
+		Email:         "invalidEmail",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedFailedFields := map[string]int{
+		"FirstName": FailLenMax,
+		"Age":       FailValMin,
+		"Email":     FailEmail,
+	}
+	expectedNames := map[string]string{
+		"FirstName": "lenmax",
+		"Age":       "valmin",
+		"Email":     "email",
+	}
+	expectedMessages := map[string]string{
+		"FirstName": "FirstName is longer than 25 characters",
+		"Age":       "Age is less than 18",
+		"Email":     "Email is not a valid email address",
+	}
+
+	valid, names := ValidateNames(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateNames returned invalid boolean value")
+	}
+	if len(names) != len(expectedNames) {
+		t.Fatalf("ValidateNames returned invalid number of failed fields %d where it should be %d", len(names), len(expectedNames))
+	}
+	for k, v := range expectedNames {
+		if len(names[k]) != 1 || names[k][0] != v {
+			t.Fatalf("ValidateNames returned invalid rule names %v where it should be %s for %s", names[k], v, k)
+		}
+	}
+
+	valid, messages := ValidateMessages(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+
+	for _, format := range []ResultFormat{ResultFlags, ResultNames, ResultMessages} {
+		valid, failed := ValidateFormatted(&s, &ValidationOptions{ResultFormat: format})
+		if valid {
+			t.Fatalf("ValidateFormatted returned invalid boolean value")
+		}
+		switch format {
+		case ResultFlags:
+			compareFailedFields(failed.(map[string]int), expectedFailedFields, t)
+		case ResultNames:
+			if len(failed.(map[string][]string)) != len(expectedNames) {
+				t.Fatalf("ValidateFormatted returned invalid number of rule names")
+			}
+		case ResultMessages:
+			compareMessages(failed.(map[string]string), expectedMessages, t)
+		}
+	}
+}
+
+func TestValidateNamesSharedFlags(t *testing.T) {
+	s := Test45{
+		Positive:    0,
+		NonNegative: -1,
+		Negative:    0,
+		NonPositive: 1,
+		Ratio:       1.5,
+		Count:       10,
+	}
+	expectedNames := map[string]string{
+		"Positive":    "gt",
+		"NonNegative": "gte",
+		"Negative":    "lt",
+		"NonPositive": "lte",
+		"Ratio":       "lte",
+		"Count":       "lt",
+	}
+	valid, names := ValidateNames(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateNames returned invalid boolean value")
+	}
+	if len(names) != len(expectedNames) {
+		t.Fatalf("ValidateNames returned invalid number of failed fields %d where it should be %d", len(names), len(expectedNames))
+	}
+	for k, v := range expectedNames {
+		if len(names[k]) != 1 || names[k][0] != v {
+			t.Fatalf("ValidateNames returned invalid rule names %v where it should be %s for %s", names[k], v, k)
+		}
+	}
+
+	valid, messages := ValidateMessages(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateMessages returned invalid boolean value")
+	}
+	if messages["Ratio"] != "Ratio must be less than or equal to 1" {
+		t.Fatalf("ValidateMessages returned invalid message '%s' for Ratio", messages["Ratio"])
+	}
+}
+
+func TestValidateNamesNumeric(t *testing.T) {
+	s := Test42{PIN: "12a4"}
+	_, names := ValidateNames(&s, &ValidationOptions{})
+	if len(names["PIN"]) != 1 || names["PIN"][0] != "numeric" {
+		t.Fatalf("ValidateNames returned invalid rule names %v where it should be numeric for PIN", names["PIN"])
+	}
+
+	c := Test58{
+		Age:      "forty",
+		Quantity: json.Number("2"),
+	}
+	_, names = ValidateNames(&c, &ValidationOptions{CoerceNumbers: true})
+	if len(names["Age"]) != 1 || names["Age"][0] != "notnumeric" {
+		t.Fatalf("ValidateNames returned invalid rule names %v where it should be notnumeric for Age", names["Age"])
+	}
+}
+
+func compareMessages(messages map[string]string, expectedMessages map[string]string, t *testing.T) {
+	if len(messages) != len(expectedMessages) {
+		t.Fatalf("Validate returned invalid number of messages %d where it should be %d", len(messages), len(expectedMessages))
+	}
+	for k, v := range expectedMessages {
+		if messages[k] != v {
+			t.Fatalf("Validate returned invalid message '%s' where it should be '%s' for %s", messages[k], v, k)
+		}
+	}
+}
+
+func TestValidateWithMessages(t *testing.T) {
+	s := Test1{
+		FirstName:     "John",
+		LastName:      "Smithsonian-Smithsonian-Smithsonian-Smithsonian-Smith",
+		Age:           300,
+		Price:         10000,
+		PostCode:      "AA123",
+		Email:         "invalidEmail",
+		BelowZero:     -7,
+		DiscountPrice: 8000,
+	}
+	expectedMessages := map[string]string{
+		"FirstName": "FirstName is shorter than 5 characters",
+		"LastName":  "LastName is longer than 50 characters",
+		"Age":       "Age is greater than 150",
+		"Price":     "Price is greater than 9999",
+		"PostCode":  "PostCode has invalid format",
+		"Email":     "Email is not a valid email address",
+		"BelowZero": "BelowZero is less than -6",
+		"Country":   "Country has invalid format",
+	}
+	valid, messages := ValidateWithMessages(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateWithMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+
+	s = Test1{}
+	expectedMessages = map[string]string{
+		"FirstName": "Podaj FirstName, FirstName is shorter than 5 characters",
+		"LastName":  "Podaj LastName, LastName is shorter than 2 characters",
+		"Age":       "Age must be at least 18 and at most 150",
+		"PostCode":  "Podaj PostCode, PostCode has invalid format",
+		"Email":     "Podaj Email, Email is not a valid email address",
+		"BelowZero": "BelowZero is greater than -2",
+		"Country":   "Country has invalid format",
+	}
+	opts := &ValidationOptions{
+		Messages: map[int]string{
+			FailEmpty:  "Podaj {field}",
+			FailValMin: "{field} must be at least {min} and at most {max}",
+		},
+	}
+	valid, messages = ValidateWithMessages(&s, opts)
+	if valid {
+		t.Fatalf("ValidateWithMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesLocale(t *testing.T) {
+	RegisterLocale("pl", map[int]string{
+		FailEmpty:  "{field} jest wymagane",
+		FailLenMin: "{field} jest krótsze niż {min} znaków",
+	})
+	s := Test1{
+		FirstName:     "John",
+		LastName:      "Smith",
+		Age:           30,
+		PostCode:      "43-155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedMessages := map[string]string{
+		"FirstName": "FirstName jest krótsze niż 5 znaków",
+	}
+	valid, messages := ValidateWithMessages(&s, &ValidationOptions{Locale: "pl"})
+	if valid {
+		t.Fatalf("ValidateWithMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+
+	s.FirstName = ""
+	s.Age = 300
+	expectedMessages = map[string]string{
+		"FirstName": "FirstName jest wymagane, Podaj imię",
+		"Age":       "Age is greater than 150",
+	}
+	opts := &ValidationOptions{
+		Locale: "pl",
+		Messages: map[int]string{
+			FailLenMin: "Podaj imię",
+		},
+	}
+	_, messages = ValidateWithMessages(&s, opts)
+	compareMessages(messages, expectedMessages, t)
+
+	s.FirstName = "John"
+	expectedMessages["FirstName"] = "FirstName is shorter than 5 characters"
+	_, messages = ValidateWithMessages(&s, &ValidationOptions{Locale: "de"})
+	compareMessages(messages, expectedMessages, t)
+}
@@ -0,0 +1,51 @@
+package structvalidator
+
+import (
+	"sync"
+)
+
+// ValidationMetrics counts failures per Fail* flag across Validate calls that have it set in
+// ValidationOptions.Metrics. It is safe for concurrent use.
+type ValidationMetrics struct {
+	mu     sync.Mutex
+	counts map[int]int64
+}
+
+// Count returns number of failures with failFlag flag.
+func (m *ValidationMetrics) Count(failFlag int) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[failFlag]
+}
+
+// Counts returns a copy of all failure counts keyed by Fail* flag.
+func (m *ValidationMetrics) Counts() map[int]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[int]int64, len(m.counts))
+	for flag, count := range m.counts {
+		counts[flag] = count
+	}
+	return counts
+}
+
+// Reset sets all failure counts to zero.
+func (m *ValidationMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts = nil
+}
+
+// add increments count of every flag set in failureFlags.
+func (m *ValidationMetrics) add(failureFlags int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = map[int]int64{}
+	}
+	for flag := 1; flag > 0 && flag <= failureFlags; flag = flag << 1 {
+		if failureFlags&flag > 0 {
+			m.counts[flag]++
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package structvalidator
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := &ValidationMetrics{}
+	opts := &ValidationOptions{
+		Metrics: metrics,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Validate(&Test1{}, opts)
+		}()
+	}
+	wg.Wait()
+
+	expectedCounts := map[int]int64{
+		FailEmpty:  80,
+		FailLenMin: 40,
+		FailRegexp: 40,
+		FailEmail:  20,
+		FailValMin: 20,
+		FailValMax: 20,
+	}
+	counts := metrics.Counts()
+	if len(counts) != len(expectedCounts) {
+		t.Fatalf("Metrics returned invalid number of counts %d where it should be %d", len(counts), len(expectedCounts))
+	}
+	for flag, count := range expectedCounts {
+		if metrics.Count(flag) != count {
+			t.Fatalf("Metrics returned invalid count %d where it should be %d for %d", metrics.Count(flag), count, flag)
+		}
+	}
+
+	metrics.Reset()
+	if len(metrics.Counts()) != 0 {
+		t.Fatalf("Metrics were not reset")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !norm
+// +build !norm
+
+package structvalidator
+
+import (
+	"fmt"
+)
+
+// checkNormalizationForm is a stub used when the package is built without the "norm" tag. Unicode normalization
+// needs golang.org/x/text, which is listed in go.mod but compiled in only with the tag, so setting
+// ValidationOptions.NormalizeUnicode without it is a configuration error.
+func checkNormalizationForm(form string) error {
+	return fmt.Errorf("NormalizeUnicode requires building with -tags norm")
+}
+
+// normalizeString returns s unchanged. It is never called since checkNormalizationForm always fails.
+func normalizeString(form string, s string) string {
+	return s
+}
@@ -0,0 +1,35 @@
+//go:build norm
+// +build norm
+
+package structvalidator
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var normalizationForms = map[string]norm.Form{
+	"NFC":  norm.NFC,
+	"NFD":  norm.NFD,
+	"NFKC": norm.NFKC,
+	"NFKD": norm.NFKD,
+}
+
+// checkNormalizationForm returns an error when form is not a name of unicode normalization form.
+func checkNormalizationForm(form string) error {
+	if _, ok := normalizationForms[form]; !ok {
+		return fmt.Errorf("invalid NormalizeUnicode form %q", form)
+	}
+	return nil
+}
+
+// normalizeString returns s converted to the unicode normalization form named by form, eg. "NFC". Form is checked
+// with checkNormalizationForm before validation so s is returned unchanged when it is unknown.
+func normalizeString(form string, s string) string {
+	f, ok := normalizationForms[form]
+	if !ok {
+		return s
+	}
+	return f.String(s)
+}
@@ -0,0 +1,39 @@
+//go:build norm
+// +build norm
+
+package structvalidator
+
+import (
+	"testing"
+)
+
+type Test10 struct {
+	City string `validation:"lenmax:7"`
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	// "Łódź" in NFD form where accented letters are followed by a combining acute accent, 9 bytes long
+	s := Test10{
+		City: "\u0141o\u0301dz\u0301",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"City": FailLenMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	// NFC turns it into 7 bytes long "Łódź" with precomposed characters, same as when it was typed in that form
+	opts := &ValidationOptions{
+		NormalizeUnicode: "NFC",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+	compare(&Test10{City: "\u0141\u00f3d\u017a"}, true, map[string]int{}, opts, t)
+}
+
+func TestNormalizeUnicodeInvalidForm(t *testing.T) {
+	s := Test10{City: "Łódź"}
+	valid, failedFields, err := ValidateSafe(&s, &ValidationOptions{NormalizeUnicode: "NFX"})
+	if valid || len(failedFields) != 0 || err == nil {
+		t.Fatalf("ValidateSafe returned invalid result for unknown normalization form")
+	}
+}
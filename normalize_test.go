@@ -0,0 +1,28 @@
+//go:build !norm
+// +build !norm
+
+package structvalidator
+
+import (
+	"testing"
+)
+
+func TestNormalizeUnicodeWithoutTag(t *testing.T) {
+	s := Test50{Country: "PL", Pair: []string{"a", "b"}}
+	opts := &ValidationOptions{
+		NormalizeUnicode: "NFC",
+		OnValidationPanic: func(name string, r interface{}) {
+			t.Fatalf("validation of %s panicked with %v", name, r)
+		},
+	}
+	valid, failedFields, err := ValidateSafe(&s, opts)
+	if valid || len(failedFields) != 0 || err == nil {
+		t.Fatalf("ValidateSafe returned invalid result when package is built without norm tag")
+	}
+	if _, _, err := ValidateStrict(&s, opts); err == nil {
+		t.Fatalf("ValidateStrict did not return an error when package is built without norm tag")
+	}
+	if valid, _ := ValidateField(&s, "Country", opts); valid {
+		t.Fatalf("ValidateField returned invalid boolean value when package is built without norm tag")
+	}
+}
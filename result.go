@@ -0,0 +1,147 @@
+package structvalidator
+
+import (
+	"reflect"
+)
+
+// ValidationResult is a result of validation returned by ValidateResult and ValidateBatch. Failures keep the order in
+// which fields were validated.
+type ValidationResult struct {
+	valid       bool
+	failed      map[string]int
+	fields      []string
+	validations map[string]*FieldValidation
+	values      map[string]reflect.Value
+	tagErr      error
+	inputErr    error
+	visiting    map[visitedStruct]bool
+}
+
+// visitedStruct identifies struct that is being validated, so that a struct that references itself, directly or
+// through other structs, is not validated again. Type is needed as struct and its first field share address.
+type visitedStruct struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+func newValidationResult() *ValidationResult {
+	return &ValidationResult{
+		valid:       true,
+		failed:      map[string]int{},
+		validations: map[string]*FieldValidation{},
+		values:      map[string]reflect.Value{},
+		visiting:    map[visitedStruct]bool{},
+	}
+}
+
+// ValidateResult validates struct the same way as Validate and returns its result as ValidationResult.
+func ValidateResult(obj interface{}, options *ValidationOptions) *ValidationResult {
+	return validate(obj, options)
+}
+
+// ValidateStrict validates struct the same way as Validate. When ValidationOptions.StrictTags is set, it also returns
+// an error describing the first field which tag has a rule that is unknown or cannot be parsed. Such rules are
+// ignored by Validate.
+func ValidateStrict(obj interface{}, options *ValidationOptions) (bool, map[string]int, error) {
+	result := validate(obj, options)
+	if result.inputErr != nil {
+		return result.valid, result.failed, result.inputErr
+	}
+	return result.valid, result.failed, result.tagErr
+}
+
+// IsValid returns true when all fields are valid.
+func (r *ValidationResult) IsValid() bool {
+	return r.valid
+}
+
+// Err returns error when obj was not a non-nil pointer to struct or options were invalid, the same as the one
+// returned by ValidateSafe.
+func (r *ValidationResult) Err() error {
+	return r.inputErr
+}
+
+// Failed returns failure flags of invalid fields, the same as the map returned by Validate.
+func (r *ValidationResult) Failed() map[string]int {
+	return r.failed
+}
+
+// FailedFields returns keys of invalid fields in the order they were validated.
+func (r *ValidationResult) FailedFields() []string {
+	fields := make([]string, len(r.fields))
+	copy(fields, r.fields)
+	return fields
+}
+
+// FirstError returns key and failure flags of the first invalid field. When all fields are valid, it returns empty
+// string and 0.
+func (r *ValidationResult) FirstError() (string, int) {
+	if len(r.fields) == 0 {
+		return "", 0
+	}
+	return r.fields[0], r.failed[r.fields[0]]
+}
+
+// FieldError is failure of a field returned by ValidateOrdered.
+type FieldError struct {
+	Field string
+	Flags int
+}
+
+// ValidateOrdered validates struct the same way as Validate and returns failures in the order fields are declared
+// in the struct. Failures of group rules and ones returned by StructValidator follow failures of fields.
+func ValidateOrdered(obj interface{}, options *ValidationOptions) (bool, []FieldError) {
+	result := validate(obj, options)
+	fieldErrors := make([]FieldError, 0, len(result.fields))
+	for _, key := range result.fields {
+		fieldErrors = append(fieldErrors, FieldError{Field: key, Flags: result.failed[key]})
+	}
+	return result.valid, fieldErrors
+}
+
+// FieldFailure describes failure of a field returned by ValidateDetailed. Value is the validated value (nil when it
+// cannot be read, eg. for unexported fields or group rules). Limit is the bound of the first failed rule of lenmin,
+// lenmax, valmin and valmax, and Len is length of the value when it is a string, slice, array or map.
+type FieldFailure struct {
+	Flags int
+	Value interface{}
+	Limit int64
+	Len   int
+}
+
+// ValidateDetailed validates struct the same way as Validate and returns failures together with values that
+// failed and bounds they were checked against.
+func ValidateDetailed(obj interface{}, options *ValidationOptions) (bool, map[string]FieldFailure) {
+	result := validate(obj, options)
+	failures := map[string]FieldFailure{}
+	for key, flags := range result.failed {
+		failure := FieldFailure{
+			Flags: flags,
+		}
+		if value := reflect.Indirect(result.values[key]); value.IsValid() {
+			if value.CanInterface() {
+				failure.Value = value.Interface()
+			}
+			switch value.Kind() {
+			case reflect.String:
+				failure.Len = len(value.String())
+			case reflect.Slice, reflect.Array, reflect.Map:
+				failure.Len = value.Len()
+			}
+		}
+		if validation := result.validations[key]; validation != nil {
+			switch {
+			case flags&FailLenMin > 0:
+				failure.Limit = int64(validation.lenMin)
+			case flags&FailLenMax > 0:
+				failure.Limit = int64(validation.lenMax)
+			case flags&FailValMin > 0:
+				failure.Limit = validation.valMin
+			case flags&FailValMax > 0:
+				failure.Limit = validation.valMax
+			}
+		}
+		failures[key] = failure
+	}
+	return result.valid, failures
+}
@@ -0,0 +1,170 @@
+package structvalidator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateResult(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           35,
+		Price:         100,
+		PostCode:      "00-123",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 50,
+		Country:       "PL",
+	}
+	result := ValidateResult(&s, &ValidationOptions{})
+	if !result.IsValid() || len(result.Failed()) != 0 || len(result.FailedFields()) != 0 {
+		t.Fatalf("ValidateResult returned invalid result for valid struct")
+	}
+	if field, flags := result.FirstError(); field != "" || flags != 0 {
+		t.Fatalf("FirstError returned %s %d for valid struct", field, flags)
+	}
+
+	s.FirstName = "John"
+	s.LastName = ""
+	s.Age = 15
+	s.Country = "Poland"
+	opts := &ValidationOptions{
+		RequireAny: [][]string{
+			[]string{"County"},
+		},
+	}
+	result = ValidateResult(&s, opts)
+	if result.IsValid() {
+		t.Fatalf("ValidateResult returned invalid boolean value")
+	}
+	compareFailedFields(result.Failed(), map[string]int{
+		"FirstName": FailLenMin,
+		"LastName":  FailEmpty | FailLenMin,
+		"Age":       FailValMin,
+		"Country":   FailRegexp,
+		"County":    FailRequireAny,
+	}, t)
+	expectedFields := []string{"FirstName", "LastName", "Age", "Country", "County"}
+	if !reflect.DeepEqual(result.FailedFields(), expectedFields) {
+		t.Fatalf("FailedFields returned %v where it should be %v", result.FailedFields(), expectedFields)
+	}
+	if field, flags := result.FirstError(); field != "FirstName" || flags != FailLenMin {
+		t.Fatalf("FirstError returned %s %d where it should be FirstName %d", field, flags, FailLenMin)
+	}
+}
+
+func TestValidateOrdered(t *testing.T) {
+	s := Test1{
+		FirstName:     "John",
+		LastName:      "Smith",
+		Age:           200,
+		Price:         100,
+		PostCode:      "00123",
+		Email:         "john",
+		BelowZero:     -4,
+		DiscountPrice: 9000,
+		Country:       "pl",
+	}
+	expectedErrors := []FieldError{
+		{Field: "FirstName", Flags: FailLenMin},
+		{Field: "Age", Flags: FailValMax},
+		{Field: "PostCode", Flags: FailRegexp},
+		{Field: "Email", Flags: FailEmail},
+		{Field: "DiscountPrice", Flags: FailValMax},
+		{Field: "Country", Flags: FailRegexp},
+	}
+	for i := 0; i < 10; i++ {
+		valid, fieldErrors := ValidateOrdered(&s, &ValidationOptions{})
+		if valid {
+			t.Fatalf("ValidateOrdered returned invalid boolean value")
+		}
+		if !reflect.DeepEqual(fieldErrors, expectedErrors) {
+			t.Fatalf("ValidateOrdered returned %v where it should be %v", fieldErrors, expectedErrors)
+		}
+	}
+}
+
+func TestValidateDetailed(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smithsonian-Smithsonian-Smithsonian-Smithsonian-Smith",
+		Age:           300,
+		Price:         100,
+		PostCode:      "00-123",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 50,
+		Country:       "PL",
+	}
+	valid, failures := ValidateDetailed(&s, &ValidationOptions{})
+	if valid || len(failures) != 2 {
+		t.Fatalf("ValidateDetailed returned invalid result")
+	}
+	expectedFailures := map[string]FieldFailure{
+		"LastName": FieldFailure{Flags: FailLenMax, Value: s.LastName, Limit: 50, Len: 53},
+		"Age":      FieldFailure{Flags: FailValMax, Value: 300, Limit: 150},
+	}
+	if !reflect.DeepEqual(failures, expectedFailures) {
+		t.Fatalf("ValidateDetailed returned %v where it should be %v", failures, expectedFailures)
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	s := Test49{
+		Name:     "John",
+		Username: "john",
+	}
+	opts := &ValidationOptions{
+		StrictTags: true,
+		RestrictFields: map[string]bool{
+			"Age": true,
+		},
+	}
+	_, _, err := ValidateStrict(&s, opts)
+	if err == nil || !strings.Contains(err.Error(), "Age") || !strings.Contains(err.Error(), "valmin:abc") {
+		t.Fatalf("ValidateStrict returned invalid error %v for malformed value", err)
+	}
+
+	opts.RestrictFields = map[string]bool{
+		"Name": true,
+	}
+	_, _, err = ValidateStrict(&s, opts)
+	if err == nil || !strings.Contains(err.Error(), "Name") || !strings.Contains(err.Error(), "lenmn:3") {
+		t.Fatalf("ValidateStrict returned invalid error %v for unknown rule", err)
+	}
+
+	opts.RestrictFields = map[string]bool{
+		"Code": true,
+	}
+	valid, failedFields, err := ValidateStrict(&s, opts)
+	if err == nil || !strings.Contains(err.Error(), "Code") || !strings.Contains(err.Error(), "regexp:(") {
+		t.Fatalf("ValidateStrict returned invalid error %v for regexp that cannot be compiled", err)
+	}
+	if valid || failedFields["Code"] != FailRegexp {
+		t.Fatalf("ValidateStrict returned invalid failures %v for regexp that cannot be compiled", failedFields)
+	}
+
+	// bool has no length nor value, so min cannot be resolved
+	opts.RestrictFields = map[string]bool{
+		"Active": true,
+	}
+	_, _, err = ValidateStrict(&s, opts)
+	if err == nil || !strings.Contains(err.Error(), "Active") || !strings.Contains(err.Error(), "min:5") {
+		t.Fatalf("ValidateStrict returned invalid error %v for min on bool", err)
+	}
+
+	opts.RestrictFields = map[string]bool{
+		"Username": true,
+	}
+	valid, failedFields, err = ValidateStrict(&s, opts)
+	if !valid || len(failedFields) != 0 || err != nil {
+		t.Fatalf("ValidateStrict returned error %v for valid tag", err)
+	}
+
+	_, _, err = ValidateStrict(&s, &ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateStrict returned error without StrictTags")
+	}
+}
@@ -0,0 +1,274 @@
+package structvalidator
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RuleSpec describes a single validation rule, eg. Name "lenmin" with Params ["5"], in a form that can be used
+// to generate validation in other places such as client side code or API docs.
+type RuleSpec struct {
+	Name   string
+	Params []string
+}
+
+// listKeywords are tag keywords which value is a comma-separated list
+var listKeywords = []string{"lenin", "valin", "unicodeclass", "dayofweek"}
+
+// LoadRulesJSON reads validation rules from JSON in the format of ValidationOptions.OverwriteFieldTags, eg.
+// {"FirstName": {"validation": "req lenmin:5", "validation_regexp": "^[A-Z]"}}, so that the rules can be kept
+// outside of the code.
+func LoadRulesJSON(r io.Reader) (map[string]map[string]string, error) {
+	rules := map[string]map[string]string{}
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ExportRules returns effective validation rules for fields of struct type t, keyed the same way as failures
+// returned by Validate. Rules come from tags, options overwriting them and options inferring them (eg.
+// ValidateWhenSuffix). Rules min and max are exported as lenmin, lenmax, valmin or valmax, depending on the field
+// type. Group rules are keyed by joined field names, eg. "Email,Phone".
+func ExportRules(t reflect.Type, options *ValidationOptions) map[string][]RuleSpec {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	tagName := options.tagName()
+	rules := map[string][]RuleSpec{}
+
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		fieldKind := indirectType(field.Type).Kind()
+		if !options.isFieldSelected(field.Name, false) || !isSupportedType(indirectType(field.Type)) {
+			continue
+		}
+
+		tagVal, tagRegexpVal := fieldTags(field, field.Name, tagName, options)
+		specs := []RuleSpec{}
+		for _, opt := range strings.Split(tagVal, options.tokenSeparator()) {
+			if opt == "" {
+				continue
+			}
+			spec := ruleSpecFromToken(canonicalToken(opt, options.kvSeparator()))
+			// min and max are exported as rules they resolve to for the field type, so that defaults and
+			// inferred rules are not added next to them
+			if spec.Name == "min" || spec.Name == "max" {
+				if keyword := boundKeyword(spec.Name, indirectType(field.Type)); keyword != "" {
+					spec.Name = keyword
+				}
+			}
+			specs = append(specs, spec)
+		}
+		if options != nil && options.FieldRegexps[field.Name] != nil {
+			specs = append(specs, RuleSpec{Name: "regexp", Params: []string{options.FieldRegexps[field.Name].String()}})
+		} else if tagRegexpVal != "" {
+			specs = append(specs, RuleSpec{Name: "regexp", Params: []string{tagRegexpVal}})
+		}
+		if options != nil && options.RequiredByDefault && !hasRuleSpec(specs, "optional") && !hasRuleSpec(specs, "req") {
+			specs = append(specs, RuleSpec{Name: "req"})
+		}
+		if options != nil && options.DefaultLenMax > 0 && isNotString(fieldKind) && !hasRuleSpec(specs, "lenmax") {
+			specs = append(specs, RuleSpec{Name: "lenmax", Params: []string{strconv.Itoa(options.DefaultLenMax)}})
+		}
+		if options != nil && options.ValidateWhenSuffix {
+			if strings.HasSuffix(field.Name, "Email") && !hasRuleSpec(specs, "email") {
+				specs = append(specs, RuleSpec{Name: "email"})
+			}
+			if strings.HasSuffix(field.Name, "Price") && !hasRuleSpec(specs, "valmin") && !hasRuleSpec(specs, "valmax") {
+				specs = append(specs, RuleSpec{Name: "valmin", Params: []string{"0"}})
+			}
+		}
+
+		if len(specs) > 0 {
+			rules[options.resultKey(t, field.Name)] = specs
+		}
+	}
+
+	if options != nil {
+		for _, group := range options.RequireAny {
+			key := options.groupResultKey(t, group)
+			rules[key] = append(rules[key], RuleSpec{Name: "requireany", Params: group})
+		}
+		for _, group := range options.MutuallyExclusive {
+			key := options.groupResultKey(t, group)
+			rules[key] = append(rules[key], RuleSpec{Name: "mutuallyexclusive", Params: group})
+		}
+	}
+
+	return rules
+}
+
+func ruleSpecFromToken(opt string) RuleSpec {
+	i := strings.Index(opt, ":")
+	if i == -1 {
+		return RuleSpec{Name: opt}
+	}
+	name, val := opt[:i], opt[i+1:]
+	if name == "oneof" {
+		return RuleSpec{Name: name, Params: strings.Split(val, "|")}
+	}
+	for _, listOpt := range listKeywords {
+		if name == listOpt {
+			return RuleSpec{Name: name, Params: strings.Split(val, ",")}
+		}
+	}
+	return RuleSpec{Name: name, Params: []string{val}}
+}
+
+func hasRuleSpec(specs []RuleSpec, name string) bool {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSet builds validation rules in code, eg. when they come from a database, instead of struct tags:
+//
+//	NewRuleSet().Field("Age").Min(18).Max(150).Required()
+//
+// Rules are kept as tag strings, so they can be used as ValidationOptions.OverwriteFieldTags or with
+// ValidateWithRules.
+type RuleSet struct {
+	fields  []string
+	tags    map[string][]string
+	regexps map[string]string
+}
+
+// FieldRules adds rules for a single field of RuleSet.
+type FieldRules struct {
+	set  *RuleSet
+	name string
+}
+
+// NewRuleSet returns an empty RuleSet.
+func NewRuleSet() *RuleSet {
+	return &RuleSet{
+		tags:    map[string][]string{},
+		regexps: map[string]string{},
+	}
+}
+
+// Field returns rules of field with name, so that they can be added.
+func (r *RuleSet) Field(name string) *FieldRules {
+	if _, ok := r.tags[name]; !ok {
+		r.fields = append(r.fields, name)
+		r.tags[name] = []string{}
+	}
+	return &FieldRules{set: r, name: name}
+}
+
+// Tags returns rules in the format of ValidationOptions.OverwriteFieldTags for tag with tagName, eg. "validation".
+func (r *RuleSet) Tags(tagName string) map[string]map[string]string {
+	tags := map[string]map[string]string{}
+	for _, name := range r.fields {
+		tags[name] = map[string]string{
+			tagName: strings.Join(r.tags[name], " "),
+		}
+		if r.regexps[name] != "" {
+			tags[name][tagName+"_regexp"] = r.regexps[name]
+		}
+	}
+	return tags
+}
+
+// ValidateWithRules validates struct with rules from ruleSet that overwrite tags of its fields. Fields that are not
+// in ruleSet are validated using their tags.
+func ValidateWithRules(obj interface{}, ruleSet *RuleSet) (bool, map[string]int) {
+	options := &ValidationOptions{}
+	options.OverwriteFieldTags = ruleSet.Tags(options.tagName())
+	return Validate(obj, options)
+}
+
+// ValidateMap validates values of data with rules written the same way as in a tag, eg. "req lenmin:5", keyed by
+// keys of data. Values are validated like struct fields of their types and failures are keyed by keys. Key that is
+// missing in data, or has nil value, is treated as an empty value. Keys of data that have no rules are not validated.
+// Value never matches a regexp rule with a pattern that cannot be compiled and fails with FailRegexp. When options
+// are invalid, (false, map[string]int{}) is returned.
+func ValidateMap(data map[string]interface{}, rules map[string]string, options *ValidationOptions) (bool, map[string]int) {
+	result := newValidationResult()
+	if options.check() != nil {
+		return false, result.failed
+	}
+	tagName := options.tagName()
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := reflect.ValueOf(data[key])
+		for value.Kind() == reflect.Ptr && !value.IsNil() {
+			value = value.Elem()
+		}
+		field := reflect.StructField{
+			Name: key,
+			Type: reflect.TypeOf((*interface{})(nil)).Elem(),
+			Tag:  reflect.StructTag(tagName + ":" + strconv.Quote(rules[key])),
+		}
+		if value.IsValid() {
+			field.Type = value.Type()
+		}
+		validation := fieldValidation(field, key, tagName, options)
+
+		valid, failureFlags := true, 0
+		switch {
+		case !value.IsValid() || value.Kind() == reflect.Ptr:
+			if validation.flags&Required > 0 {
+				valid, failureFlags = false, FailEmpty
+			}
+		case isSupportedType(value.Type()):
+			valid, failureFlags = validateValueRecovered(key, value, &validation, options)
+		}
+		if !valid {
+			result.valid = false
+			options.addFailure(result, key, failureFlags, &validation)
+		}
+	}
+	return result.valid, result.failed
+}
+
+// Field returns rules of another field of the same RuleSet.
+func (f *FieldRules) Field(name string) *FieldRules {
+	return f.set.Field(name)
+}
+
+// Rule adds rule written the same way as in a tag, eg. "lenin:2,3".
+func (f *FieldRules) Rule(rule string) *FieldRules {
+	f.set.tags[f.name] = append(f.set.tags[f.name], rule)
+	return f
+}
+
+// Required adds req rule.
+func (f *FieldRules) Required() *FieldRules {
+	return f.Rule("req")
+}
+
+// Min adds min rule, which is minimum length or value depending on field type.
+func (f *FieldRules) Min(min int) *FieldRules {
+	return f.Rule("min:" + strconv.Itoa(min))
+}
+
+// Max adds max rule, which is maximum length or value depending on field type.
+func (f *FieldRules) Max(max int) *FieldRules {
+	return f.Rule("max:" + strconv.Itoa(max))
+}
+
+// Email adds email rule.
+func (f *FieldRules) Email() *FieldRules {
+	return f.Rule("email")
+}
+
+// Regexp sets regular expression that field must match.
+func (f *FieldRules) Regexp(pattern string) *FieldRules {
+	f.set.regexps[f.name] = pattern
+	return f
+}
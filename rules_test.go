@@ -0,0 +1,218 @@
+package structvalidator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRulesJSON(t *testing.T) {
+	rules, err := LoadRulesJSON(strings.NewReader(`{
+		"FirstName": {"validation": "req lenmin:2 lenmax:10"},
+		"County": {"validation": "req", "validation_regexp": "^[A-Z][a-z]+$"}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadRulesJSON returned error: %s", err.Error())
+	}
+	if rules["FirstName"]["validation"] != "req lenmin:2 lenmax:10" || rules["County"]["validation_regexp"] != "^[A-Z][a-z]+$" {
+		t.Fatalf("LoadRulesJSON returned invalid rules")
+	}
+
+	s := Test1{
+		FirstName: "123456789012345678901234567890",
+		County:    "enfield",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"FirstName": FailLenMax,
+		"County":    FailRegexp,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"FirstName": true,
+			"County":    true,
+		},
+		OverwriteFieldTags: rules,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	rules, err = LoadRulesJSON(strings.NewReader(`{"County": {"validation_regexp": "^[A-Z"}}`))
+	if err != nil {
+		t.Fatalf("LoadRulesJSON returned error: %s", err.Error())
+	}
+	opts.OverwriteFieldTags = rules
+	s.County = "Enfield"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	_, err = LoadRulesJSON(strings.NewReader(`{"FirstName": "req"}`))
+	if err == nil {
+		t.Fatalf("LoadRulesJSON did not return error for invalid rules")
+	}
+}
+
+func TestExportRules(t *testing.T) {
+	expectedRules := map[string][]RuleSpec{
+		"FirstName":     []RuleSpec{{Name: "req"}, {Name: "lenmin", Params: []string{"5"}}, {Name: "lenmax", Params: []string{"25"}}},
+		"LastName":      []RuleSpec{{Name: "req"}, {Name: "lenmin", Params: []string{"2"}}, {Name: "lenmax", Params: []string{"50"}}},
+		"Age":           []RuleSpec{{Name: "req"}, {Name: "valmin", Params: []string{"18"}}, {Name: "valmax", Params: []string{"150"}}},
+		"Price":         []RuleSpec{{Name: "req"}, {Name: "valmin", Params: []string{"0"}}, {Name: "valmax", Params: []string{"9999"}}},
+		"PostCode":      []RuleSpec{{Name: "req"}, {Name: "regexp", Params: []string{"^[0-9][0-9]-[0-9][0-9][0-9]$"}}},
+		"Email":         []RuleSpec{{Name: "req"}, {Name: "email"}},
+		"BelowZero":     []RuleSpec{{Name: "valmin", Params: []string{"-6"}}, {Name: "valmax", Params: []string{"-2"}}},
+		"DiscountPrice": []RuleSpec{{Name: "valmin", Params: []string{"0"}}, {Name: "valmax", Params: []string{"8000"}}},
+		"Country":       []RuleSpec{{Name: "regexp", Params: []string{"^[A-Z][A-Z]$"}}},
+		"County":        []RuleSpec{{Name: "lenmax", Params: []string{"40"}}},
+	}
+	rules := ExportRules(reflect.TypeOf(Test1{}), nil)
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Fatalf("ExportRules returned invalid rules %v", rules)
+	}
+
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"FirstName": true,
+			"Country":   true,
+		},
+		OverwriteFieldTags: map[string]map[string]string{
+			"FirstName": map[string]string{
+				"validation": "lenin:4,6",
+			},
+		},
+		RequireAny: [][]string{
+			[]string{"Email", "Phone"},
+		},
+		DefaultLenMax: 100,
+	}
+	expectedRules = map[string][]RuleSpec{
+		"FirstName":   []RuleSpec{{Name: "lenin", Params: []string{"4", "6"}}, {Name: "lenmax", Params: []string{"100"}}},
+		"Country":     []RuleSpec{{Name: "regexp", Params: []string{"^[A-Z][A-Z]$"}}, {Name: "lenmax", Params: []string{"100"}}},
+		"Email,Phone": []RuleSpec{{Name: "requireany", Params: []string{"Email", "Phone"}}},
+	}
+	rules = ExportRules(reflect.TypeOf(&Test1{}), opts)
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Fatalf("ExportRules returned invalid rules %v", rules)
+	}
+
+	opts = &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"FirstName":     true,
+			"DiscountPrice": true,
+		},
+		OverwriteFieldTags: map[string]map[string]string{
+			"FirstName":     map[string]string{"validation": "max:10"},
+			"DiscountPrice": map[string]string{"validation": "min:100"},
+		},
+		DefaultLenMax:      100,
+		ValidateWhenSuffix: true,
+	}
+	expectedRules = map[string][]RuleSpec{
+		"FirstName":     []RuleSpec{{Name: "lenmax", Params: []string{"10"}}},
+		"DiscountPrice": []RuleSpec{{Name: "valmin", Params: []string{"100"}}},
+	}
+	rules = ExportRules(reflect.TypeOf(&Test1{}), opts)
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Fatalf("ExportRules returned invalid rules %v", rules)
+	}
+}
+
+func TestRuleSet(t *testing.T) {
+	ruleSet := NewRuleSet()
+	ruleSet.Field("Name").Required().Min(3).
+		Field("Age").Min(18).Max(150).Required().
+		Field("Email").Email().
+		Field("PostCode").Regexp("^[0-9]{2}-[0-9]{3}$")
+
+	expectedTags := map[string]map[string]string{
+		"Name":     {"validation": "req min:3"},
+		"Age":      {"validation": "min:18 max:150 req"},
+		"Email":    {"validation": "email"},
+		"PostCode": {"validation": "", "validation_regexp": "^[0-9]{2}-[0-9]{3}$"},
+	}
+	if !reflect.DeepEqual(ruleSet.Tags("validation"), expectedTags) {
+		t.Fatalf("RuleSet returned tags %v where they should be %v", ruleSet.Tags("validation"), expectedTags)
+	}
+
+	s := Test48{
+		Name:     "Jo",
+		Age:      15,
+		Email:    "invalid",
+		PostCode: "12345",
+		Comment:  "Way too long comment",
+	}
+	valid, failedFields := ValidateWithRules(&s, ruleSet)
+	if valid {
+		t.Fatalf("ValidateWithRules returned invalid boolean value")
+	}
+	compareFailedFields(failedFields, map[string]int{
+		"Name":     FailLenMin,
+		"Age":      FailValMin,
+		"Email":    FailEmail,
+		"PostCode": FailRegexp,
+		"Comment":  FailLenMax,
+	}, t)
+
+	s = Test48{
+		Name:     "John",
+		Age:      35,
+		Email:    "john@example.com",
+		PostCode: "12-345",
+	}
+	valid, failedFields = ValidateWithRules(&s, ruleSet)
+	if !valid || len(failedFields) != 0 {
+		t.Fatalf("ValidateWithRules returned failures for valid struct")
+	}
+}
+
+func TestValidateMap(t *testing.T) {
+	rules := map[string]string{
+		"age":      "req valmin:18 valmax:150",
+		"email":    "req email",
+		"nickname": "lenmin:3",
+	}
+	data := map[string]interface{}{
+		"age":   35,
+		"email": "john@example.com",
+		"other": "",
+	}
+	valid, failedFields := ValidateMap(data, rules, &ValidationOptions{})
+	if !valid {
+		t.Fatalf("ValidateMap returned invalid boolean value")
+	}
+	compareFailedFields(failedFields, map[string]int{}, t)
+
+	data = map[string]interface{}{
+		"age":      int64(12),
+		"email":    "john",
+		"nickname": "Jo",
+	}
+	valid, failedFields = ValidateMap(data, rules, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateMap returned invalid boolean value")
+	}
+	compareFailedFields(failedFields, map[string]int{
+		"age":      FailValMin,
+		"email":    FailEmail,
+		"nickname": FailLenMin,
+	}, t)
+
+	data = map[string]interface{}{
+		"email": nil,
+	}
+	valid, failedFields = ValidateMap(data, rules, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateMap returned invalid boolean value")
+	}
+	compareFailedFields(failedFields, map[string]int{
+		"age":   FailEmpty,
+		"email": FailEmpty,
+	}, t)
+
+	// pattern that cannot be compiled is never matched
+	valid, failedFields = ValidateMap(map[string]interface{}{"code": "x"}, map[string]string{"code": "regexp:("}, nil)
+	if valid {
+		t.Fatalf("ValidateMap returned invalid boolean value")
+	}
+	compareFailedFields(failedFields, map[string]int{
+		"code": FailRegexp,
+	}, t)
+}
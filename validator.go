@@ -1,10 +1,22 @@
 package structvalidator
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
 )
 
 type FieldValidation struct {
@@ -14,6 +26,47 @@ type FieldValidation struct {
 	valMax int64
 	regexp *regexp.Regexp
 	flags  int64
+
+	regexpGroup    string
+	invalidRegexp  bool
+	valueBounds    []string
+	unicodeClasses []*unicode.RangeTable
+	lenIn          []int
+	valIn          []int64
+	maxDecimals    int
+	daysOfWeek     []time.Weekday
+	formatField    string
+	lenEqField     string
+	reqWith        string
+	powerOf        int64
+	oneOf          []string
+	customRules    []string
+	byteMin        int
+	byteMax        int
+	elem           *FieldValidation
+	mapVal         *FieldValidation
+	before         time.Time
+	after          time.Time
+	eqField        string
+	neField        string
+	contains       []string
+	startsWith     string
+	endsWith       string
+	dateTime       string
+	excludes       []string
+	gt             *float64
+	gte            *float64
+	lt             *float64
+	lte            *float64
+	emailRegexp    *regexp.Regexp
+	password       *PasswordPolicy
+	coerceNumbers  bool
+	defaultValue   reflect.Value
+	length         int
+	unknownRules   []string
+	invalidRules   []string
+	externalRules  []func(reflect.Value) (bool, int)
+	customFuncs    []func(reflect.Value) (bool, int)
 }
 
 // values used with flags
@@ -21,8 +74,35 @@ const ValMinNotNil = 2
 const ValMaxNotNil = 4
 const Required = 8
 const Email = 16
+const Positive = 32
+const Negative = 64
+const NonNegative = 128
+const NonPositive = 256
+const Bcrypt = 512
+const Weekday = 1024
+const FileMode = 2048
+const Optional = 4096
+const Color = 8192
+const NotBlank = 16384
+const URL = 32768
+const UUID = 65536
+const MustBeTrue = 131072
+const MustBeFalse = 262144
+const Alpha = 524288
+const Numeric = 1048576
+const AlphaNumeric = 2097152
+const OmitEmpty = 4194304
+const IP = 8388608
+const IPv4 = 16777216
+const IPv6 = 33554432
+const CIDR = 67108864
+const Password = 134217728
+const HexColor = 268435456
+const Base64 = 536870912
+const Hex = 1073741824
 
-// values for invalid field flags
+// values for invalid field flags. Rules of the same kind share a flag, eg. gt, gte, after and valmin all fail with
+// FailValMin; rule names returned by ValidateNames tell them apart.
 const FailLenMin = 2
 const FailLenMax = 4
 const FailValMin = 8
@@ -31,207 +111,2076 @@ const FailEmpty = 32
 const FailRegexp = 64
 const FailEmail = 128
 const FailZero = 256
+const FailRegexpGroup = 512
+const FailSign = 1024
+const FailFormat = 2048
+const FailCustom = 4096
+const FailExternal = 8192
+const FailOverwriteType = 16384
+const FailUnicodeClass = 32768
+const FailLenIn = 65536
+const FailTimeout = 131072
+const FailPanic = 262144
+const FailRequireAny = 524288
+const FailMutuallyExclusive = 1048576
+const FailNotNumeric = 2097152
+const FailValIn = 4194304
+const FailBudgetExceeded = 8388608
+const FailMaxDecimals = 16777216
+const FailEqField = 33554432
+const FailFormatField = 67108864
+const FailElem = 1073741824
+
+// rules that share flags with the ones above
+const FailPositive = FailSign
+const FailNegative = FailSign
+const FailNonNegative = FailSign
+const FailNonPositive = FailSign
+const FailBcrypt = FailFormat
+const FailFileMode = FailFormat
+const FailColor = FailFormat
+const FailURL = FailFormat
+const FailUUID = FailFormat
+const FailIP = FailFormat
+const FailCIDR = FailFormat
+const FailPassword = FailFormat
+const FailDateTime = FailFormat
+const FailHexColor = FailFormat
+const FailBase64 = FailFormat
+const FailHex = FailFormat
+const FailWeekday = FailValIn
+const FailPowerOf = FailValIn
+const FailOneOf = FailValIn
+const FailBool = FailValIn
+const FailLenEqField = FailLenIn
+const FailLen = FailLenIn
+const FailByteMin = FailLenMin
+const FailByteMax = FailLenMax
+const FailBlank = FailEmpty
+const FailMapVal = FailElem
+const FailAfter = FailValMin
+const FailBefore = FailValMax
+const FailGt = FailValMin
+const FailGte = FailValMin
+const FailLt = FailValMax
+const FailLte = FailValMax
+const FailNeField = FailEqField
+const FailCharset = FailUnicodeClass
+const FailContains = FailRegexp
+const FailExcludes = FailRegexp
+const FailStartsWith = FailRegexp
+const FailEndsWith = FailRegexp
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// regexpCache keeps regular expressions from tags compiled, keyed by their pattern
+var regexpCache sync.Map
+
+var emailRegexp = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+var uuidRegexp = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+var alphaRegexp = regexp.MustCompile(`^[a-zA-Z]+$`)
+
+var numericRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+var alphaNumericRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+var bcryptRegexp = regexp.MustCompile(`^\$2[aby]\$(0[4-9]|[12][0-9]|3[01])\$[./A-Za-z0-9]{53}$`)
+
+var hexColorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+var hexRegexp = regexp.MustCompile(`^(0[xX])?[0-9a-fA-F]+$`)
+
+var colorRegexp = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|rgb\(\s*(25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\s*,\s*(25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\s*,\s*(25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\s*\))$`)
+
+// colorNames are CSS named colors accepted by color rule
+var colorNames = map[string]bool{
+	"black": true, "silver": true, "gray": true, "grey": true, "white": true, "maroon": true, "red": true,
+	"purple": true, "fuchsia": true, "green": true, "lime": true, "olive": true, "yellow": true, "navy": true,
+	"blue": true, "teal": true, "aqua": true, "orange": true, "pink": true, "brown": true, "transparent": true,
+}
+
+// PasswordPolicy defines what value of field with password rule must contain. Length is counted in characters and
+// special character is any that is not a letter, digit or space.
+type PasswordPolicy struct {
+	MinLen         int
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// defaultPasswordPolicy is used by password rule when ValidationOptions.PasswordPolicy is nil
+var defaultPasswordPolicy = &PasswordPolicy{
+	MinLen:         8,
+	RequireUpper:   true,
+	RequireDigit:   true,
+	RequireSpecial: true,
+}
 
 // Optional configuration for validation:
-// * RestrictFields defines what struct fields should be validated
+// * RestrictFields defines what struct fields should be validated; keys can contain "*" wildcard, eg. "Address*"
 // * OverwriteFieldTags can be used to overwrite tags for specific fields
 // * OverwriteTagName sets tag used to define validation (default is "validation")
 // * ValidateWhenSuffix will validate certain fields based on their name, eg. "PrimaryEmail" field will need to be a valid email
 // * OverwriteFieldValues is to use overwrite values for fields, so these values are validated not the ones in struct
+// * DefaultLenMax sets maximum length for string fields that do not have lenmax in their tag (0 means no default)
+// * OverwriteFieldValuesTyped works like OverwriteFieldValues but value must be of a kind compatible with the field, otherwise field fails with FailOverwriteType (takes precedence over OverwriteFieldValues)
+// * ResultFormat sets shape of failures returned by ValidateFormatted (flags, rule names or messages)
+// * ExternalRuleResolver is called with every tag token that is not a built-in rule, eg. "nospaces" or "divisible:3"; when it returns true, the returned func is used to validate the field; field fails with FailExternal together with flags returned by the func
+// * NormalizeUnicode converts string values to given normalization form ("NFC", "NFD", "NFKC" or "NFKD") before validation; package must be built with "norm" tag to use it (golang.org/x/text is required by go.mod but compiled in only with the tag), otherwise, or when form is unknown, validation fails with an error returned by ValidateSafe
+// * CustomValidatorTimeout limits time each rule returned by ExternalRuleResolver, and each validator used with "custom:name" tag token, can take; field fails with FailTimeout when it runs longer
+// * FieldMatcher decides whether field with given name should be validated; it is checked together with RestrictFields
+// * OnValidationPanic is called when validation of a field panics, eg. in a rule from ExternalRuleResolver; field fails with FailPanic either way
+// * RequireAny lists groups of fields where at least one field in each group must not be empty; failure is keyed by field names joined with "," and has FailRequireAny flag
+// * MutuallyExclusive lists groups of fields where at most one field in each group can be non-empty; failure is keyed like in RequireAny and has FailMutuallyExclusive flag
+// * FieldRegexps sets compiled regular expressions for fields, overriding the ones defined in tags
+// * FieldAliases renames fields in the returned map of failures, eg. "FirstName" to "first_name"
+// * RuleTimeoutBudget limits total time of validation; when it is exceeded, validation stops and the first field that was not validated fails with FailBudgetExceeded
+// * DisableDedupeFailures turns off merging of failures reported under the same key (eg. field and a single-field group rule, or two fields with the same alias); by default their flags are ORed, with this option the last one wins; merging is turned off rather than on, so that it stays the default for zero value of ValidationOptions and for nil options
+// * ShortCircuitFieldFunc is called with field name and validated struct; when it returns true the field is not validated
+// * Metrics accumulates counts of failures per Fail* flag; the same ValidationMetrics can be shared by many concurrent Validate calls
+// * EmitJSONPointer makes failures keyed by JSON Pointers (RFC 6901) built from json tag names, eg. "/first_name"
+// * RequiredByDefault makes all fields required unless they are marked with "optional" in their tag
+// * Messages overwrites default message templates used by ValidateWithMessages, keyed by Fail* constant; templates can contain {field}, {min} and {max} placeholders; a template applies to all rules that share the flag
+// * SkipValidationTag names a bool field of the validated struct; when it is true, struct is not validated and is considered valid, eg. for drafts
+// * Recursive makes fields that are structs, or pointers to structs, validated as well; their failures are keyed with field names joined with ".", eg. "Address.PostCode"; struct reached again through a cycle of pointers is not validated the second time; RestrictFields, SkipFields, FieldMatcher, OverwriteFieldTags and FieldRegexps refer to their fields by such qualified names, and selecting or skipping a struct selects or skips all its fields
+// * CustomValidators sets validators used with "custom:name" tag token for this call only; they take precedence over ones added with RegisterValidator
+// * SkipFields defines struct fields that should not be validated, with keys written like in RestrictFields; field listed in both RestrictFields and SkipFields is skipped
+// * UseJSONNames makes failures keyed by names from json tags, eg. "first_name"; Go field name is used when field has no json tag or it is "-"
+// * EmailPattern sets regular expression used by email rule instead of the built-in one; when it cannot be compiled, validation fails with an error returned by ValidateSafe
+// * ValidateEmbedded makes fields of embedded structs, or pointers to structs, validated as if they were fields of the struct that embeds them; their failures are keyed with promoted field names; structs of unexported types are validated too and ShortCircuitFieldFunc gets the embedding struct for their fields
+// * StrictTags makes ValidateStrict return an error when a tag has a rule that is unknown or has a value that cannot be parsed, eg. "valmin:abc"
+// * Locale selects message templates added with RegisterLocale used by ValidateWithMessages; messages without a template in the locale are in English
+// * PasswordPolicy sets what is required from fields with password rule; when it is nil, password must have at least 8 characters including an uppercase letter, a digit and a special character
+// * CoerceNumbers makes string fields with valmin, valmax, valin, gt, gte, lt, lte, powerof or sign rules parsed as numbers and these rules checked against the number; field fails with FailNotNumeric when value is not a number (json.Number is always parsed)
+// * ApplyDefaults sets string, int, uint and float fields that have zero value, or are nil pointers, to value from "default:" tag token before they are validated; validated struct is modified
+// * TokenSeparator sets string that separates tokens in tags instead of " ", eg. ";" allows values with spaces
+// * KVSeparator sets string that separates keyword from its value in tag tokens instead of ":", eg. "=" in "lenmin=5"
 type ValidationOptions struct {
 	RestrictFields       map[string]bool
 	OverwriteFieldTags   map[string]map[string]string
 	OverwriteTagName     string
 	ValidateWhenSuffix   bool
 	OverwriteFieldValues map[string]interface{}
+	DefaultLenMax        int
+
+	OverwriteFieldValuesTyped map[string]reflect.Value
+	ResultFormat              ResultFormat
+	ExternalRuleResolver      func(ruleName string) (func(reflect.Value) (bool, int), bool)
+	NormalizeUnicode          string
+	CustomValidatorTimeout    time.Duration
+	FieldMatcher              func(name string) bool
+	OnValidationPanic         func(field string, r interface{})
+	RequireAny                [][]string
+	MutuallyExclusive         [][]string
+	FieldRegexps              map[string]*regexp.Regexp
+	FieldAliases              map[string]string
+	RuleTimeoutBudget         time.Duration
+	DisableDedupeFailures     bool
+	ShortCircuitFieldFunc     func(field string, obj interface{}) bool
+	Metrics                   *ValidationMetrics
+	EmitJSONPointer           bool
+	RequiredByDefault         bool
+	Messages                  map[int]string
+	SkipValidationTag         string
+	Recursive                 bool
+	CustomValidators          map[string]func(reflect.Value) bool
+	SkipFields                map[string]bool
+	UseJSONNames              bool
+	EmailPattern              string
+	ValidateEmbedded          bool
+	StrictTags                bool
+	Locale                    string
+	PasswordPolicy            *PasswordPolicy
+	CoerceNumbers             bool
+	ApplyDefaults             bool
+	TokenSeparator            string
+	KVSeparator               string
+}
+
+// StructValidator can be implemented by validated struct to add rules that span multiple fields. ValidateStruct is
+// called after fields are validated and its failures are added to the ones returned by Validate. When a key is
+// returned by both, flags are merged the same way as failures of group rules (see DisableDedupeFailures).
+type StructValidator interface {
+	ValidateStruct() map[string]int
 }
 
-// Validate validates fields of a struct. Currently only fields which are string or int (any) are validated.
+// Validate validates fields of a struct. Currently only fields which are string, int (any), float, bool, time.Time,
+// slice, array or map, or pointers to them, are validated. Nil pointer is treated as an empty value and []byte is
+// validated as a string.
 // Func returns boolean value that determines whether value is true or false, and a map of fields that failed
-// validation. See Fail* constants for the values.
+// validation. See Fail* constants for the values; flags of all the rules a field failed on are combined, use
+// HasFailure to check for a specific one. When obj is not a non-nil pointer to struct or options are invalid,
+// (false, map[string]int{}) is returned, see ValidateSafe.
 func Validate(obj interface{}, options *ValidationOptions) (bool, map[string]int) {
+	result := validate(obj, options)
+	return result.valid, result.failed
+}
+
+// ValidateSafe validates struct the same way as Validate and additionally returns an error when obj is not a
+// non-nil pointer to struct or options are invalid, eg. NormalizeUnicode is set to an unknown form. Validate returns
+// (false, map[string]int{}) in such case.
+func ValidateSafe(obj interface{}, options *ValidationOptions) (bool, map[string]int, error) {
+	result := validate(obj, options)
+	return result.valid, result.failed, result.inputErr
+}
+
+// check returns an error when options cannot be used for validation.
+func (o *ValidationOptions) check() error {
+	if o == nil {
+		return nil
+	}
+	if o.NormalizeUnicode != "" {
+		if err := checkNormalizationForm(o.NormalizeUnicode); err != nil {
+			return err
+		}
+	}
+	if o.EmailPattern != "" {
+		if _, err := regexp.Compile(o.EmailPattern); err != nil {
+			return fmt.Errorf("invalid EmailPattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkStructPointer returns an error when obj cannot be validated because it is not a non-nil pointer to struct.
+func checkStructPointer(obj interface{}) error {
+	if obj == nil {
+		return fmt.Errorf("cannot validate nil")
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("cannot validate %s, pointer to struct is required", v.Type())
+	}
+	if v.IsNil() {
+		return fmt.Errorf("cannot validate nil %s", v.Type())
+	}
+	if v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot validate %s, pointer to struct is required", v.Type())
+	}
+	return nil
+}
+
+// ValidateSlice validates each struct in slice objs with Validate and returns failures keyed by index of element.
+// Only elements that failed have an entry. Slice can hold structs or pointers to structs, and elements that are not
+// structs or are nil are skipped. When objs is not a slice, or pointer to one, (false, nil) is returned.
+func ValidateSlice(objs interface{}, options *ValidationOptions) (bool, map[int]map[string]int) {
+	v := reflect.Indirect(reflect.ValueOf(objs))
+	if v.Kind() != reflect.Slice {
+		return false, nil
+	}
+	valid := true
+	failed := map[int]map[string]int{}
+	for j := 0; j < v.Len(); j++ {
+		elem := v.Index(j)
+		if elem.Kind() == reflect.Ptr && elem.IsNil() {
+			continue
+		}
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		if elem.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		elemValid, elemFailed := Validate(elem.Interface(), options)
+		if !elemValid {
+			valid = false
+			failed[j] = elemFailed
+		}
+	}
+	return valid, failed
+}
+
+// ValidateBatch validates each of objs the same way as ValidateResult using workers goroutines and returns their
+// results in the same order as objs. Result of an object that is not a non-nil pointer to struct is invalid and has
+// error returned by ValidationResult.Err. Options are shared by all the goroutines.
+func ValidateBatch(objs []interface{}, options *ValidationOptions, workers int) []*ValidationResult {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]*ValidationResult, len(objs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range indexes {
+				results[j] = validate(objs[j], options)
+			}
+		}()
+	}
+	for j := range objs {
+		indexes <- j
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// validate validates struct like Validate and returns result that additionally has validations of struct fields
+// that failed, keyed the same way as failures.
+func validate(obj interface{}, options *ValidationOptions) *ValidationResult {
+	result := newValidationResult()
+
+	if err := checkStructPointer(obj); err != nil {
+		result.valid = false
+		result.inputErr = err
+		return result
+	}
+	if err := options.check(); err != nil {
+		result.valid = false
+		result.inputErr = err
+		return result
+	}
+
 	v := reflect.ValueOf(obj)
+	if options.isSkipped(v) {
+		return result
+	}
+
+	valid, completed := validateStruct(v, obj, "", "", options, time.Now(), result)
+	if !completed {
+		result.valid = false
+		return result
+	}
+
+	if options != nil && !validateGroups(v, options, result) {
+		valid = false
+	}
+
+	if structValidator, ok := obj.(StructValidator); ok {
+		for key, failureFlags := range structValidator.ValidateStruct() {
+			valid = false
+			options.addFailure(result, key, failureFlags, nil)
+		}
+	}
+
+	result.valid = valid
+	return result
+}
+
+// validateStruct validates fields of struct pointed by v and adds their failures to result, with keys prefixed
+// with prefix. Path is the name of the struct qualified with names of fields it is nested in, eg. "Address", used to
+// select fields with options. Obj is passed to ShortCircuitFieldFunc. Second returned value is false when
+// validation was stopped because RuleTimeoutBudget was exceeded.
+func validateStruct(v reflect.Value, obj interface{}, prefix string, path string, options *ValidationOptions, start time.Time, result *ValidationResult) (bool, bool) {
 	i := reflect.Indirect(v)
 	s := i.Type()
 
-	tagName := "validation"
-	if options != nil && options.OverwriteTagName != "" {
-		tagName = options.OverwriteTagName
+	// struct that is already being validated is reached again through a cycle of pointers
+	visited := visitedStruct{ptr: v.Pointer(), t: s}
+	if result.visiting[visited] {
+		return true, true
 	}
+	result.visiting[visited] = true
+	defer delete(result.visiting, visited)
 
-	invalidFields := map[string]int{}
+	tagName := options.tagName()
 	valid := true
 
 	for j := 0; j < s.NumField(); j++ {
 		field := s.Field(j)
-		fieldKind := field.Type.Kind()
+		name := qualifiedName(path, field.Name)
+		nested := options != nil && (options.Recursive || options.ValidateEmbedded && field.Anonymous) && isNestedStruct(field)
+		promoted := nested && options.ValidateEmbedded && field.Anonymous
 
-		// check if only specified field should be checked
-		if options != nil && len(options.RestrictFields) > 0 && !options.RestrictFields[field.Name] {
+		// check if only specified field should be checked; fields of embedded struct are selected one by one
+		if promoted && isFieldOrParentInSet(options.SkipFields, name) || !promoted && !options.isFieldSelected(name, nested) {
 			continue
 		}
-
-		// validate only ints and string
-		if !isNotInt(fieldKind) && !isNotString(fieldKind) {
+		if options != nil && options.ShortCircuitFieldFunc != nil && options.ShortCircuitFieldFunc(field.Name, obj) {
 			continue
 		}
 
-		validation := FieldValidation{}
-		validation.lenMin = -1
-		validation.lenMax = -1
-
-		// get tag values
-		tagVal := field.Tag.Get(tagName)
-		tagRegexpVal := field.Tag.Get(tagName + "_regexp")
-		if options != nil && len(options.OverwriteFieldTags) > 0 {
-			if len(options.OverwriteFieldTags[field.Name]) > 0 {
-				if options.OverwriteFieldTags[field.Name][tagName] != "" {
-					tagVal = options.OverwriteFieldTags[field.Name][tagName]
-				}
-				if options.OverwriteFieldTags[field.Name][tagName+"_regexp"] != "" {
-					tagRegexpVal = options.OverwriteFieldTags[field.Name][tagName+"_regexp"]
+		if nested {
+			nestedValue := i.Field(j)
+			if nestedValue.Kind() == reflect.Ptr {
+				if nestedValue.IsNil() {
+					continue
 				}
+			} else {
+				nestedValue = nestedValue.Addr()
 			}
+			if options.isSkipped(nestedValue) {
+				continue
+			}
+			// fields of embedded struct are keyed and selected as if they were promoted
+			nestedPrefix, nestedPath := options.nestedKey(prefix, options.resultKey(s, field.Name)), name
+			if promoted {
+				nestedPrefix, nestedPath = prefix, path
+			}
+			// embedded struct of unexported type cannot be turned into interface, its fields are promoted to obj
+			nestedObj := obj
+			if nestedValue.CanInterface() {
+				nestedObj = nestedValue.Interface()
+			}
+			nestedValid, completed := validateStruct(nestedValue, nestedObj, nestedPrefix, nestedPath, options, start, result)
+			if !nestedValid {
+				valid = false
+			}
+			if !completed {
+				return false, false
+			}
+			continue
 		}
 
-		setValidationFromTag(&validation, tagVal)
-		if tagRegexpVal != "" {
-			validation.regexp = regexp.MustCompile(tagRegexpVal)
+		// validate only ints, floats, string, bool, time, slices, arrays, maps and interfaces, or pointers to them
+		if !isSupportedType(indirectType(field.Type)) {
+			continue
 		}
 
-		if options != nil && options.ValidateWhenSuffix {
-			if strings.HasSuffix(field.Name, "Email") {
-				validation.flags = validation.flags | Email
-			}
-			if strings.HasSuffix(field.Name, "Price") && validation.valMin == 0 && validation.valMax == 0 && validation.flags&ValMinNotNil == 0 && validation.flags&ValMaxNotNil == 0 {
-				validation.valMin = 0
-				validation.flags = validation.flags | ValMinNotNil
-			}
+		if options != nil && options.RuleTimeoutBudget > 0 && time.Since(start) > options.RuleTimeoutBudget {
+			options.addFailure(result, options.nestedKey(prefix, options.resultKey(s, field.Name)), FailBudgetExceeded, nil)
+			return false, false
 		}
 
-		var fieldValue reflect.Value
-		if options != nil && len(options.OverwriteFieldValues) > 0 && isKeyInMap(field.Name, options.OverwriteFieldValues) {
-			fieldValue = reflect.ValueOf(options.OverwriteFieldValues[field.Name])
-		} else {
-			fieldValue = v.Elem().FieldByName(field.Name)
+		validation := fieldValidation(field, name, tagName, options)
+		if options != nil && options.ApplyDefaults && validation.defaultValue.IsValid() {
+			applyDefault(i.Field(j), validation.defaultValue)
 		}
-
-		fieldValid, failureFlags := validateValue(fieldValue, &validation)
+		if options != nil && options.StrictTags && len(validation.invalidRules) > 0 && result.tagErr == nil {
+			result.tagErr = fmt.Errorf("field %s has invalid rule %q", options.nestedKey(prefix, field.Name), validation.invalidRules[0])
+		}
+		fieldValid, failureFlags := validateStructField(v, field, &validation, options)
 		if !fieldValid {
 			valid = false
-			invalidFields[field.Name] = failureFlags
+			key := options.nestedKey(prefix, options.resultKey(s, field.Name))
+			options.addFailure(result, key, failureFlags, &validation)
+			result.values[key], _ = structFieldValue(v, field, options)
 		}
 	}
 
-	return valid, invalidFields
+	return valid, true
 }
 
-func validateValue(value reflect.Value, validation *FieldValidation) (bool, int) {
-	minCanBeZero := false
-	maxCanBeZero := false
-	if validation.flags&ValMinNotNil > 0 {
-		minCanBeZero = true
+// isNestedStruct returns true when field is an exported or embedded struct, or pointer to struct, that can be
+// validated recursively.
+func isNestedStruct(field reflect.StructField) bool {
+	t := indirectType(field.Type)
+	return (field.PkgPath == "" || field.Anonymous) && t.Kind() == reflect.Struct && t != timeType
+}
+
+// ValidateField validates a single field of a struct the same way Validate does and returns whether it is valid
+// and its failure flags. Options that select fields, such as RestrictFields, are ignored. When obj is not a pointer
+// to struct, options are invalid or struct has no field with such name, (false, 0) is returned. Fields of types that are not validated are always valid.
+// Field promoted from nil pointer to embedded struct is empty.
+func ValidateField(obj interface{}, fieldName string, options *ValidationOptions) (bool, int) {
+	if checkStructPointer(obj) != nil || options.check() != nil {
+		return false, 0
 	}
-	if validation.flags&ValMaxNotNil > 0 {
-		maxCanBeZero = true
+	v := reflect.ValueOf(obj)
+	field, ok := reflect.Indirect(v).Type().FieldByName(fieldName)
+	if !ok {
+		return false, 0
 	}
-
-	if validation.flags&Required > 0 {
-		if value.Type().Name() == "string" && value.String() == "" {
-			return false, FailEmpty
+	if !isSupportedType(indirectType(field.Type)) {
+		return true, 0
+	}
+	validation := fieldValidation(field, field.Name, options.tagName(), options)
+	if options != nil && options.ApplyDefaults && validation.defaultValue.IsValid() {
+		if fieldValue := fieldByIndex(v.Elem(), field.Index); fieldValue.IsValid() {
+			applyDefault(fieldValue, validation.defaultValue)
 		}
-		if strings.HasPrefix(value.Type().Name(), "int") && value.Int() == 0 && !minCanBeZero && !maxCanBeZero && validation.valMin == 0 && validation.valMax == 0 {
-			return false, FailZero
+	}
+	return validateStructField(v, field, &validation, options)
+}
+
+// fieldValidation returns validation of struct field built from its tags and options. Name is used to look the field
+// up in options, and is qualified for fields of nested structs, eg. "Address.PostCode".
+func fieldValidation(field reflect.StructField, name string, tagName string, options *ValidationOptions) FieldValidation {
+	fieldKind := indirectType(field.Type).Kind()
+
+	validation := FieldValidation{}
+	validation.lenMin = -1
+	validation.lenMax = -1
+	validation.maxDecimals = -1
+	validation.length = -1
+
+	tagVal, tagRegexpVal := fieldTags(field, name, tagName, options)
+
+	setValidationFromTag(&validation, tagVal, indirectType(field.Type), options.tokenSeparator(), options.kvSeparator())
+	if tagRegexpVal != "" {
+		re, err := compileRegexp(tagRegexpVal)
+		if err != nil {
+			validation.invalidRegexp = true
+			validation.invalidRules = append(validation.invalidRules, tagName+"_regexp:"+tagRegexpVal)
 		}
+		validation.regexp = re
+	}
+	if options != nil && options.FieldRegexps[name] != nil {
+		validation.regexp = options.FieldRegexps[name]
 	}
 
-	if value.Type().Name() == "string" {
-		if validation.lenMin > 0 && len(value.String()) < validation.lenMin {
-			return false, FailLenMin
+	if options != nil && options.EmailPattern != "" {
+		// pattern is checked by ValidationOptions.check before fields are validated
+		validation.emailRegexp, _ = compileRegexp(options.EmailPattern)
+	}
+
+	if options != nil && options.CoerceNumbers {
+		validation.coerceNumbers = true
+	}
+
+	if validation.flags&Password > 0 {
+		validation.password = defaultPasswordPolicy
+		if options != nil && options.PasswordPolicy != nil {
+			validation.password = options.PasswordPolicy
 		}
-		if validation.lenMax > 0 && len(value.String()) > validation.lenMax {
-			return false, FailLenMax
+	}
+
+	if options != nil && options.ExternalRuleResolver != nil {
+		for _, ruleName := range validation.unknownRules {
+			if rule, ok := options.ExternalRuleResolver(ruleName); ok {
+				if options.CustomValidatorTimeout > 0 {
+					rule = ruleWithTimeout(rule, options.CustomValidatorTimeout)
+				}
+				validation.externalRules = append(validation.externalRules, rule)
+			} else {
+				validation.invalidRules = append(validation.invalidRules, ruleName)
+			}
 		}
+	} else {
+		validation.invalidRules = append(validation.invalidRules, validation.unknownRules...)
+	}
 
-		if validation.regexp != nil {
-			if !validation.regexp.MatchString(value.String()) {
-				return false, FailRegexp
+	for _, name := range validation.customRules {
+		if fn, ok := customValidator(name, options); ok {
+			rule := customRule(fn)
+			if options != nil && options.CustomValidatorTimeout > 0 {
+				rule = ruleWithTimeout(rule, options.CustomValidatorTimeout)
 			}
+			validation.customFuncs = append(validation.customFuncs, rule)
 		}
+	}
 
-		if validation.flags&Email > 0 {
-			var emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
-			if !emailRegex.MatchString(value.String()) {
-				return false, FailEmail
+	if options != nil && options.RequiredByDefault && validation.flags&(Optional|OmitEmpty) == 0 {
+		validation.flags = validation.flags | Required
+	}
+
+	if options != nil && options.DefaultLenMax > 0 && isNotString(fieldKind) && validation.lenMax == -1 {
+		validation.lenMax = options.DefaultLenMax
+	}
+
+	if options != nil && options.ValidateWhenSuffix {
+		if strings.HasSuffix(field.Name, "Email") {
+			validation.flags = validation.flags | Email
+		}
+		if strings.HasSuffix(field.Name, "Price") && validation.valMin == 0 && validation.valMax == 0 && validation.flags&ValMinNotNil == 0 && validation.flags&ValMaxNotNil == 0 {
+			validation.valMin = 0
+			validation.flags = validation.flags | ValMinNotNil
+		}
+	}
+
+	return validation
+}
+
+// validateStructField validates field of struct pointed by v and returns whether it is valid and its failure flags.
+func validateStructField(v reflect.Value, field reflect.StructField, validation *FieldValidation, options *ValidationOptions) (bool, int) {
+	if validation.formatField != "" && !setValidationFromFormatField(v, validation, options) {
+		return false, FailFormatField
+	}
+	if validation.reqWith != "" && !isFieldEmpty(v, validation.reqWith, options) {
+		validation.flags = validation.flags | Required
+	}
+
+	fieldValue, ok := structFieldValue(v, field, options)
+	if !ok {
+		return false, FailOverwriteType
+	}
+
+	// nil interface or pointer, and field promoted from nil pointer to embedded struct, is an empty value, other
+	// rules do not apply to it. Interface is validated by its concrete value, which can be a pointer as well.
+	for !fieldValue.IsValid() || fieldValue.Kind() == reflect.Interface || fieldValue.Kind() == reflect.Ptr {
+		if !fieldValue.IsValid() || fieldValue.IsNil() {
+			if validation.flags&Required > 0 {
+				return false, FailEmpty
 			}
+			return true, 0
 		}
+		fieldValue = fieldValue.Elem()
+	}
+	if fieldValue.IsValid() && !isSupportedType(fieldValue.Type()) {
+		return true, 0
+	}
+	if len(validation.valueBounds) > 0 && fieldValue.IsValid() {
+		setValidationFromTag(validation, strings.Join(validation.valueBounds, " "), fieldValue.Type(), " ", ":")
 	}
 
-	if strings.HasPrefix(value.Type().Name(), "int") {
-		if (validation.valMin != 0 || minCanBeZero) && validation.valMin > value.Int() {
-			return false, FailValMin
+	if options != nil && options.NormalizeUnicode != "" && fieldValue.Kind() == reflect.String {
+		fieldValue = reflect.ValueOf(normalizeString(options.NormalizeUnicode, fieldValue.String()))
+	}
+
+	valid, failureFlags := validateValueRecovered(field.Name, fieldValue, validation, options)
+	if validation.lenEqField != "" && !isLenEqualToField(v, fieldValue, validation.lenEqField, options) {
+		valid = false
+		failureFlags = failureFlags | FailLenEqField
+	}
+	if validation.eqField != "" {
+		if equal, ok := isEqualToField(v, fieldValue, validation.eqField, options); !ok || !equal {
+			valid = false
+			failureFlags = failureFlags | FailEqField
 		}
-		if (validation.valMax != 0 || maxCanBeZero) && validation.valMax < value.Int() {
-			return false, FailValMax
+	}
+	if validation.neField != "" {
+		if equal, ok := isEqualToField(v, fieldValue, validation.neField, options); !ok || equal {
+			valid = false
+			failureFlags = failureFlags | FailNeField
 		}
 	}
+	return valid, failureFlags
+}
 
-	return true, 0
+// structFieldValue returns value of field of struct pointed by v, or its overwrite value. It returns false when
+// value from OverwriteFieldValuesTyped is not compatible with the field.
+func structFieldValue(v reflect.Value, field reflect.StructField, options *ValidationOptions) (reflect.Value, bool) {
+	if typedValue, ok := options.typedFieldValue(field.Name); ok {
+		if !typedValue.IsValid() || !isKindCompatible(indirectType(field.Type).Kind(), indirectType(typedValue.Type()).Kind()) {
+			return typedValue, false
+		}
+		return typedValue, true
+	}
+	return siblingValue(v, field.Name, options), true
 }
 
-func setValidationFromTag(v *FieldValidation, tag string) {
-	opts := strings.SplitN(tag, " ", -1)
-	for _, opt := range opts {
-		if opt == "req" {
-			v.flags = v.flags | Required
+// validateGroups checks rules that apply to groups of fields and adds failures to result. Failures are keyed
+// by names of fields in the group joined with ",".
+func validateGroups(v reflect.Value, options *ValidationOptions, result *ValidationResult) bool {
+	valid := true
+	for _, group := range options.RequireAny {
+		nonEmpty := 0
+		for _, name := range group {
+			if !isFieldEmpty(v, name, options) {
+				nonEmpty++
+			}
 		}
-		if opt == "email" {
-			v.flags = v.flags | Email
+		if nonEmpty == 0 {
+			valid = false
+			options.addFailure(result, options.groupResultKey(v.Elem().Type(), group), FailRequireAny, nil)
 		}
-		for _, valOpt := range []string{"lenmin", "lenmax", "valmin", "valmax", "regexp"} {
-			if strings.HasPrefix(opt, valOpt+":") {
-				val := strings.Replace(opt, valOpt+":", "", 1)
-				if valOpt == "regexp" {
-					v.regexp = regexp.MustCompile(val)
-					continue
-				}
+	}
+	for _, group := range options.MutuallyExclusive {
+		nonEmpty := 0
+		for _, name := range group {
+			if !isFieldEmpty(v, name, options) {
+				nonEmpty++
+			}
+		}
+		if nonEmpty > 1 {
+			valid = false
+			options.addFailure(result, options.groupResultKey(v.Elem().Type(), group), FailMutuallyExclusive, nil)
+		}
+	}
+	return valid
+}
 
-				i, err := strconv.Atoi(val)
-				if err != nil {
-					continue
-				}
-				switch valOpt {
-				case "lenmin":
-					v.lenMin = i
-				case "lenmax":
-					v.lenMax = i
-				case "valmin":
-					v.valMin = int64(i)
-					if i == 0 {
-						v.flags = v.flags | ValMinNotNil
-					}
-				case "valmax":
-					v.valMax = int64(i)
-					if i == 0 {
-						v.flags = v.flags | ValMaxNotNil
-					}
-				}
+// isFieldEmpty returns true when field value (or its overwrite value) is a zero value. Fields that do not exist are
+// considered empty.
+func isFieldEmpty(v reflect.Value, name string, options *ValidationOptions) bool {
+	fieldValue := siblingValue(v, name, options)
+	return !fieldValue.IsValid() || fieldValue.IsZero()
+}
+
+// siblingValue returns value of another field of the validated struct, or its overwrite value, typed overwrite
+// value taking precedence. Returned value is invalid when field does not exist.
+func siblingValue(v reflect.Value, name string, options *ValidationOptions) reflect.Value {
+	if typedValue, ok := options.typedFieldValue(name); ok {
+		return typedValue
+	}
+	if options != nil && len(options.OverwriteFieldValues) > 0 && isKeyInMap(name, options.OverwriteFieldValues) {
+		return reflect.ValueOf(options.OverwriteFieldValues[name])
+	}
+	return fieldByName(v.Elem(), name)
+}
+
+// fieldByName returns field of struct v like reflect.Value.FieldByName does, but instead of panicking it returns
+// invalid value when the field is promoted from nil pointer to embedded struct.
+func fieldByName(v reflect.Value, name string) reflect.Value {
+	field, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}
+	}
+	return fieldByIndex(v, field.Index)
+}
+
+// fieldByIndex returns nested field of struct v like reflect.Value.FieldByIndex does, returning invalid value when
+// the field is promoted from nil pointer to embedded struct.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
 			}
+			v = v.Elem()
 		}
+		v = v.Field(x)
 	}
+	return v
 }
 
-func isNotInt(k reflect.Kind) bool {
-	if k == reflect.Int64 || k == reflect.Int32 || k == reflect.Int16 || k == reflect.Int8 || k == reflect.Int || k == reflect.Uint64 || k == reflect.Uint32 || k == reflect.Uint16 || k == reflect.Uint8 || k == reflect.Uint {
+// isLenEqualToField returns true when length of value equals value of the int field referenced with leneqfield. It
+// returns false when the field does not exist or is not an int.
+func isLenEqualToField(v reflect.Value, value reflect.Value, name string, options *ValidationOptions) bool {
+	count := siblingValue(v, name, options)
+	if !count.IsValid() || !isNotInt(count.Kind()) {
+		return false
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+	default:
+		return false
+	}
+	if isUint(count.Kind()) {
+		return uint64(value.Len()) == count.Uint()
+	}
+	return int64(value.Len()) == count.Int()
+}
+
+// isEqualToField compares string, int or float value with value of the field referenced with eqfield or nefield.
+// Second returned value is false when the field does not exist or values cannot be compared.
+func isEqualToField(v reflect.Value, value reflect.Value, name string, options *ValidationOptions) (bool, bool) {
+	other := reflect.Indirect(siblingValue(v, name, options))
+	switch {
+	case !other.IsValid():
+		return false, false
+	case isNotString(value.Kind()) && isNotString(other.Kind()):
+		return value.String() == other.String(), true
+	case isUint(value.Kind()) && isUint(other.Kind()):
+		return value.Uint() == other.Uint(), true
+	case isNotInt(value.Kind()) && !isUint(value.Kind()) && isNotInt(other.Kind()) && !isUint(other.Kind()):
+		return value.Int() == other.Int(), true
+	case isFloat(value.Kind()) && isFloat(other.Kind()):
+		return value.Float() == other.Float(), true
+	}
+	return false, false
+}
+
+// formatFlags maps format names that can be used with formatfield to flags of rules validating them
+var formatFlags = map[string]int64{
+	"email": Email,
+	"url":   URL,
+	"uuid":  UUID,
+	"ip":    IP,
+	"cidr":  CIDR,
+}
+
+// setValidationFromFormatField sets flag of format named by value of the field referenced with formatfield. It
+// returns false when the field does not exist or its value is not a known format.
+func setValidationFromFormatField(v reflect.Value, validation *FieldValidation, options *ValidationOptions) bool {
+	format := siblingValue(v, validation.formatField, options)
+	if !format.IsValid() || format.Kind() != reflect.String {
+		return false
+	}
+	if format.String() == "" {
 		return true
 	}
+	flag, ok := formatFlags[format.String()]
+	if !ok {
+		return false
+	}
+	validation.flags = validation.flags | flag
+	return true
+}
+
+// isSkipped returns true when bool field named by SkipValidationTag is set in struct pointed by v.
+func (o *ValidationOptions) isSkipped(v reflect.Value) bool {
+	if o == nil || o.SkipValidationTag == "" {
+		return false
+	}
+	skip := fieldByName(v.Elem(), o.SkipValidationTag)
+	return skip.IsValid() && skip.Kind() == reflect.Bool && skip.Bool()
+}
+
+// isFieldSelected returns false when field should not be validated because of RestrictFields, SkipFields or
+// FieldMatcher. Name of field of nested struct is qualified, eg. "Address.PostCode"; selecting or skipping a struct
+// selects or skips its fields as well. Nested struct is also selected when any of its fields is.
+func (o *ValidationOptions) isFieldSelected(name string, nested bool) bool {
+	if o != nil && isFieldOrParentInSet(o.SkipFields, name) {
+		return false
+	}
+	if o != nil && len(o.RestrictFields) > 0 && !isFieldOrParentInSet(o.RestrictFields, name) && !(nested && hasChildInSet(o.RestrictFields, name)) {
+		return false
+	}
+	if o != nil && o.FieldMatcher != nil && !o.FieldMatcher(name) {
+		return false
+	}
+	return true
+}
+
+// qualifiedName returns name of field of struct that is nested under path, eg. "Address.PostCode".
+func qualifiedName(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// isFieldOrParentInSet returns true when qualified field name, or name of any struct the field is nested in, is in
+// fields.
+func isFieldOrParentInSet(fields map[string]bool, name string) bool {
+	for {
+		if isFieldInSet(fields, name) {
+			return true
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return false
+		}
+		name = name[:i]
+	}
+}
+
+// hasChildInSet returns true when fields have a field nested in struct with qualified name.
+func hasChildInSet(fields map[string]bool, name string) bool {
+	for key, selected := range fields {
+		if selected && strings.HasPrefix(key, name+".") {
+			return true
+		}
+	}
 	return false
 }
 
-func isNotString(k reflect.Kind) bool {
-	if k == reflect.String {
+// isFieldInSet returns true when field name is set to true in fields, either as is or by a key with "*" wildcard,
+// eg. "Address*". Keys are matched with path.Match.
+func isFieldInSet(fields map[string]bool, name string) bool {
+	if fields[name] {
 		return true
 	}
+	for pattern, selected := range fields {
+		if !selected || !strings.Contains(pattern, "*") {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenSeparator returns string that separates tokens in tags.
+func (o *ValidationOptions) tokenSeparator() string {
+	if o != nil && o.TokenSeparator != "" {
+		return o.TokenSeparator
+	}
+	return " "
+}
+
+// kvSeparator returns string that separates keyword from its value in tag tokens.
+func (o *ValidationOptions) kvSeparator() string {
+	if o != nil && o.KVSeparator != "" {
+		return o.KVSeparator
+	}
+	return ":"
+}
+
+// canonicalToken returns tag token with keyword and value separated with kvSep written with ":", eg. "lenmin=5"
+// becomes "lenmin:5". Value is left as it is.
+func canonicalToken(opt string, kvSep string) string {
+	if kvSep == ":" {
+		return opt
+	}
+	if i := strings.Index(opt, kvSep); i > -1 {
+		return opt[:i] + ":" + opt[i+len(kvSep):]
+	}
+	return opt
+}
+
+// tagName returns name of the tag that defines validation.
+func (o *ValidationOptions) tagName() string {
+	if o != nil && o.OverwriteTagName != "" {
+		return o.OverwriteTagName
+	}
+	return "validation"
+}
+
+// fieldTags returns values of validation and regexp tags for field, taking OverwriteFieldTags for field with name
+// into account.
+func fieldTags(field reflect.StructField, name string, tagName string, options *ValidationOptions) (string, string) {
+	tagVal := field.Tag.Get(tagName)
+	tagRegexpVal := field.Tag.Get(tagName + "_regexp")
+	if options != nil && len(options.OverwriteFieldTags) > 0 {
+		if len(options.OverwriteFieldTags[name]) > 0 {
+			if options.OverwriteFieldTags[name][tagName] != "" {
+				tagVal = options.OverwriteFieldTags[name][tagName]
+			}
+			if options.OverwriteFieldTags[name][tagName+"_regexp"] != "" {
+				tagRegexpVal = options.OverwriteFieldTags[name][tagName+"_regexp"]
+			}
+		}
+	}
+	return tagVal, tagRegexpVal
+}
+
+// resultKey returns name under which failure of field of struct type t is stored in the result map.
+func (o *ValidationOptions) resultKey(t reflect.Type, name string) string {
+	key := name
+	if o != nil && o.FieldAliases[name] != "" {
+		key = o.FieldAliases[name]
+	} else if o != nil && (o.EmitJSONPointer || o.UseJSONNames) {
+		key = jsonFieldName(t, name)
+	}
+	if o != nil && o.EmitJSONPointer {
+		key = "/" + jsonPointerEscaper.Replace(key)
+	}
+	return key
+}
+
+// nestedKey returns key of failure of nested struct field, eg. "Address.PostCode", or "/address/post_code" with
+// EmitJSONPointer.
+func (o *ValidationOptions) nestedKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if o != nil && o.EmitJSONPointer {
+		return prefix + key
+	}
+	return prefix + "." + key
+}
+
+// groupResultKey returns name under which failure of a group of fields is stored in the result map.
+func (o *ValidationOptions) groupResultKey(t reflect.Type, group []string) string {
+	keys := make([]string, len(group))
+	for i, name := range group {
+		keys[i] = o.resultKey(t, name)
+	}
+	return strings.Join(keys, ",")
+}
+
+// jsonPointerEscaper escapes reference tokens of JSON Pointer as defined in RFC 6901
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonFieldName returns name of field of struct type t from its json tag. Go field name is returned when the
+// field has no json tag, the tag has no name or it is "-".
+func jsonFieldName(t reflect.Type, name string) string {
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return name
+	}
+	jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+	if jsonName == "" || jsonName == "-" {
+		return name
+	}
+	return jsonName
+}
+
+// addFailure stores failure flags under key in result, merging them with flags already stored there unless
+// DisableDedupeFailures is set. Failure is also counted in Metrics. Validation is stored for field failures and is
+// nil for other ones.
+func (o *ValidationOptions) addFailure(result *ValidationResult, key string, failureFlags int, validation *FieldValidation) {
+	if o != nil && o.Metrics != nil {
+		o.Metrics.add(failureFlags)
+	}
+	if _, ok := result.failed[key]; !ok {
+		result.fields = append(result.fields, key)
+	}
+	if validation != nil {
+		result.validations[key] = validation
+	}
+	if o != nil && o.DisableDedupeFailures {
+		result.failed[key] = failureFlags
+		return
+	}
+	result.failed[key] = result.failed[key] | failureFlags
+}
+
+func (o *ValidationOptions) typedFieldValue(name string) (reflect.Value, bool) {
+	if o == nil || len(o.OverwriteFieldValuesTyped) == 0 {
+		return reflect.Value{}, false
+	}
+	v, ok := o.OverwriteFieldValuesTyped[name]
+	return v, ok
+}
+
+// HasFailure returns true when failure flags returned for a field contain failConst, eg. FailLenMin.
+func HasFailure(flags int, failConst int) bool {
+	return flags&failConst == failConst
+}
+
+// validateValueRecovered calls validateValue and turns a panic into FailPanic failure, passing it to
+// OnValidationPanic when it is set.
+func validateValueRecovered(name string, value reflect.Value, validation *FieldValidation, options *ValidationOptions) (valid bool, failureFlags int) {
+	defer func() {
+		if r := recover(); r != nil {
+			if options != nil && options.OnValidationPanic != nil {
+				options.OnValidationPanic(name, r)
+			}
+			valid = false
+			failureFlags = FailPanic
+		}
+	}()
+	return validateValue(value, validation)
+}
+
+// validateValue checks value against all rules in validation and returns whether it is valid and flags of all
+// the rules that failed.
+func validateValue(value reflect.Value, validation *FieldValidation) (bool, int) {
+	valid := true
+	failureFlags := 0
+	fail := func(failureFlag int) {
+		valid = false
+		failureFlags = failureFlags | failureFlag
+	}
+
+	// []byte is validated as a string
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+		value = reflect.ValueOf(string(value.Bytes()))
+	}
+
+	// json.Number is validated as a string and numeric rules are checked against the number it holds
+	coerceNumbers := validation.coerceNumbers
+	if value.Type() == jsonNumberType {
+		value = reflect.ValueOf(value.String())
+		coerceNumbers = true
+	}
+
+	// zero value of field with omitempty passes all rules
+	if validation.flags&OmitEmpty > 0 && value.IsZero() {
+		return true, 0
+	}
+
+	minCanBeZero := false
+	maxCanBeZero := false
+	if validation.flags&ValMinNotNil > 0 {
+		minCanBeZero = true
+	}
+	if validation.flags&ValMaxNotNil > 0 {
+		maxCanBeZero = true
+	}
+
+	if validation.flags&Required > 0 {
+		if value.Type().Name() == "string" && value.String() == "" {
+			fail(FailEmpty)
+		}
+		if strings.HasPrefix(value.Type().Name(), "int") && value.Int() == 0 && !minCanBeZero && !maxCanBeZero && validation.valMin == 0 && validation.valMax == 0 {
+			fail(FailZero)
+		}
+		if value.Type() == timeType && value.IsZero() {
+			fail(FailEmpty)
+		}
+		if isUint(value.Kind()) && value.Uint() == 0 && !minCanBeZero && !maxCanBeZero && validation.valMin == 0 && validation.valMax == 0 {
+			fail(FailZero)
+		}
+		if isFloat(value.Kind()) && value.Float() == 0 && !minCanBeZero && !maxCanBeZero && validation.valMin == 0 && validation.valMax == 0 {
+			fail(FailZero)
+		}
+		if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array || value.Kind() == reflect.Map) && value.Len() == 0 {
+			fail(FailEmpty)
+		}
+	}
+
+	if value.Type().Name() == "string" {
+		if validation.flags&NotBlank > 0 && strings.TrimSpace(value.String()) == "" {
+			fail(FailBlank)
+		}
+
+		// length of string is its size in bytes, the same for len, lenmin, lenmax, lenin, bytemin and bytemax
+		if validation.lenMin > 0 && len(value.String()) < validation.lenMin {
+			fail(FailLenMin)
+		}
+		if validation.lenMax > 0 && len(value.String()) > validation.lenMax {
+			fail(FailLenMax)
+		}
+		if len(validation.lenIn) > 0 && value.String() != "" && !isIntInSlice(len(value.String()), validation.lenIn) {
+			fail(FailLenIn)
+		}
+		if validation.length > -1 && len(value.String()) != validation.length {
+			fail(FailLen)
+		}
+		if validation.byteMin > 0 && len(value.String()) < validation.byteMin {
+			fail(FailByteMin)
+		}
+		if validation.byteMax > 0 && len(value.String()) > validation.byteMax {
+			fail(FailByteMax)
+		}
+
+		// value never matches a pattern that cannot be compiled
+		if validation.invalidRegexp && validation.regexp == nil {
+			fail(FailRegexp)
+		}
+		if validation.regexp != nil {
+			if !validation.regexp.MatchString(value.String()) {
+				fail(FailRegexp)
+			} else if validation.regexpGroup != "" {
+				groupIndex := validation.regexp.SubexpIndex(validation.regexpGroup)
+				if groupIndex < 0 || validation.regexp.FindStringSubmatch(value.String())[groupIndex] == "" {
+					fail(FailRegexpGroup)
+				}
+			}
+		}
+
+		if len(validation.unicodeClasses) > 0 {
+			for _, r := range value.String() {
+				if !unicode.IsOneOf(validation.unicodeClasses, r) {
+					fail(FailUnicodeClass)
+					break
+				}
+			}
+		}
+
+		if validation.flags&Email > 0 {
+			re := emailRegexp
+			if validation.emailRegexp != nil {
+				re = validation.emailRegexp
+			}
+			if !re.MatchString(value.String()) {
+				fail(FailEmail)
+			}
+		}
+
+		if validation.password != nil && value.String() != "" && !isPasswordCompliant(value.String(), validation.password) {
+			fail(FailPassword)
+		}
+
+		if validation.dateTime != "" && value.String() != "" {
+			if _, err := time.Parse(validation.dateTime, value.String()); err != nil {
+				fail(FailDateTime)
+			}
+		}
+
+		if validation.startsWith != "" && value.String() != "" && !strings.HasPrefix(value.String(), validation.startsWith) {
+			fail(FailStartsWith)
+		}
+		if validation.endsWith != "" && value.String() != "" && !strings.HasSuffix(value.String(), validation.endsWith) {
+			fail(FailEndsWith)
+		}
+
+		for _, substr := range validation.contains {
+			if !strings.Contains(value.String(), substr) {
+				fail(FailContains)
+				break
+			}
+		}
+		for _, substr := range validation.excludes {
+			if strings.Contains(value.String(), substr) {
+				fail(FailExcludes)
+				break
+			}
+		}
+
+		if validation.flags&URL > 0 && value.String() != "" && !isURL(value.String()) {
+			fail(FailURL)
+		}
+
+		if value.String() != "" && ((validation.flags&Alpha > 0 && !alphaRegexp.MatchString(value.String())) ||
+			(validation.flags&Numeric > 0 && !numericRegexp.MatchString(value.String())) ||
+			(validation.flags&AlphaNumeric > 0 && !alphaNumericRegexp.MatchString(value.String()))) {
+			fail(FailCharset)
+		}
+
+		if validation.flags&UUID > 0 && value.String() != "" && !uuidRegexp.MatchString(value.String()) {
+			fail(FailUUID)
+		}
+
+		if validation.flags&(IP|IPv4|IPv6) > 0 && value.String() != "" && !isIP(value.String(), validation.flags) {
+			fail(FailIP)
+		}
+
+		if validation.flags&CIDR > 0 && value.String() != "" {
+			if _, _, err := net.ParseCIDR(value.String()); err != nil {
+				fail(FailCIDR)
+			}
+		}
+
+		if validation.flags&Color > 0 && value.String() != "" && !colorRegexp.MatchString(value.String()) && !colorNames[strings.ToLower(value.String())] {
+			fail(FailColor)
+		}
+
+		if validation.flags&HexColor > 0 && value.String() != "" && !hexColorRegexp.MatchString(value.String()) {
+			fail(FailHexColor)
+		}
+
+		if validation.flags&Base64 > 0 && value.String() != "" {
+			if _, err := base64.StdEncoding.DecodeString(value.String()); err != nil {
+				fail(FailBase64)
+			}
+		}
+
+		if validation.flags&Hex > 0 && value.String() != "" && !hexRegexp.MatchString(value.String()) {
+			fail(FailHex)
+		}
+
+		if validation.flags&Bcrypt > 0 && value.String() != "" && !bcryptRegexp.MatchString(value.String()) {
+			fail(FailBcrypt)
+		}
+
+		if validation.maxDecimals > -1 && value.String() != "" {
+			f, err := strconv.ParseFloat(value.String(), 64)
+			if err != nil || countDecimals(f, 64) > validation.maxDecimals {
+				fail(FailMaxDecimals)
+			}
+		}
+	}
+
+	// rest of rules is checked against the number that string holds
+	if coerceNumbers && isNotString(value.Kind()) && value.String() != "" && hasNumericRules(validation) {
+		if number, ok := parseNumber(value.String()); ok {
+			value = number
+		} else {
+			fail(FailNotNumeric)
+		}
+	}
+
+	if strings.HasPrefix(value.Type().Name(), "int") || value.Type() == durationType {
+		if (validation.valMin != 0 || minCanBeZero) && validation.valMin > value.Int() {
+			fail(FailValMin)
+		}
+		if (validation.valMax != 0 || maxCanBeZero) && validation.valMax < value.Int() {
+			fail(FailValMax)
+		}
+		if len(validation.valIn) > 0 && !isInt64InSlice(value.Int(), validation.valIn) {
+			fail(FailValIn)
+		}
+	}
+
+	// unsigned value is always greater than negative valmin and never lower than negative valmax
+	if isUint(value.Kind()) {
+		if validation.valMin > 0 && uint64(validation.valMin) > value.Uint() {
+			fail(FailValMin)
+		}
+		if (validation.valMax != 0 || maxCanBeZero) && (validation.valMax < 0 || uint64(validation.valMax) < value.Uint()) {
+			fail(FailValMax)
+		}
+		if len(validation.valIn) > 0 && (value.Uint() > math.MaxInt64 || !isInt64InSlice(int64(value.Uint()), validation.valIn)) {
+			fail(FailValIn)
+		}
+	}
+
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array || value.Kind() == reflect.Map {
+		if validation.lenMin > 0 && value.Len() < validation.lenMin {
+			fail(FailLenMin)
+		}
+		if validation.lenMax > 0 && value.Len() > validation.lenMax {
+			fail(FailLenMax)
+		}
+		if validation.length > -1 && value.Len() != validation.length {
+			fail(FailLen)
+		}
+	}
+
+	if value.Kind() == reflect.Map && validation.mapVal != nil {
+		iter := value.MapRange()
+		for iter.Next() {
+			if ok, _ := validateValue(iter.Value(), validation.mapVal); !ok {
+				fail(FailMapVal)
+				break
+			}
+		}
+	}
+
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		if validation.elem != nil {
+			for i := 0; i < value.Len(); i++ {
+				if ok, _ := validateValue(value.Index(i), validation.elem); !ok {
+					fail(FailElem)
+					break
+				}
+			}
+		}
+	}
+
+	if validation.gt != nil || validation.gte != nil || validation.lt != nil || validation.lte != nil {
+		if n, ok := numberValue(value); ok {
+			if validation.gt != nil && !(n > *validation.gt) {
+				fail(FailGt)
+			}
+			if validation.gte != nil && !(n >= *validation.gte) {
+				fail(FailGte)
+			}
+			if validation.lt != nil && !(n < *validation.lt) {
+				fail(FailLt)
+			}
+			if validation.lte != nil && !(n <= *validation.lte) {
+				fail(FailLte)
+			}
+		}
+	}
+
+	if isFloat(value.Kind()) {
+		if (validation.valMin != 0 || minCanBeZero) && float64(validation.valMin) > value.Float() {
+			fail(FailValMin)
+		}
+		if (validation.valMax != 0 || maxCanBeZero) && float64(validation.valMax) < value.Float() {
+			fail(FailValMax)
+		}
+		// value with a fraction is never in the set of ints
+		if len(validation.valIn) > 0 && (value.Float() != math.Trunc(value.Float()) || !isInt64InSlice(int64(value.Float()), validation.valIn)) {
+			fail(FailValIn)
+		}
+		if validation.maxDecimals > -1 && countDecimals(value.Float(), value.Type().Bits()) > validation.maxDecimals {
+			fail(FailMaxDecimals)
+		}
+	}
+
+	if t, ok := timeValue(value); ok && !t.IsZero() {
+		if validation.flags&Weekday > 0 && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+			fail(FailWeekday)
+		}
+		if len(validation.daysOfWeek) > 0 && !isWeekdayInSlice(t.Weekday(), validation.daysOfWeek) {
+			fail(FailWeekday)
+		}
+		if !validation.before.IsZero() && !t.Before(validation.before) {
+			fail(FailBefore)
+		}
+		if !validation.after.IsZero() && !t.After(validation.after) {
+			fail(FailAfter)
+		}
+	}
+
+	if value.Kind() == reflect.Bool {
+		if validation.flags&MustBeTrue > 0 && !value.Bool() {
+			fail(FailBool)
+		}
+		if validation.flags&MustBeFalse > 0 && value.Bool() {
+			fail(FailBool)
+		}
+	}
+
+	if validation.flags&(Positive|Negative|NonNegative|NonPositive) > 0 {
+		if ok, failureFlag := validateSign(value, validation); !ok {
+			fail(failureFlag)
+		}
+	}
+
+	if len(validation.oneOf) > 0 && !value.IsZero() && !isOneOf(value, validation.oneOf) {
+		fail(FailOneOf)
+	}
+
+	if validation.powerOf > 0 && !isPowerOf(value, validation.powerOf) {
+		fail(FailPowerOf)
+	}
+
+	if validation.flags&FileMode > 0 && !isFileMode(value) {
+		fail(FailFileMode)
+	}
+
+	for _, rule := range validation.customFuncs {
+		if ok, failureFlag := rule(value); !ok {
+			fail(failureFlag)
+		}
+	}
+
+	for _, rule := range validation.externalRules {
+		if ok, failureFlag := rule(value); !ok {
+			fail(FailExternal | failureFlag)
+		}
+	}
+
+	return valid, failureFlags
+}
+
+// ruleWithTimeout wraps rule so that it fails with FailTimeout when it does not return within timeout. Rule keeps
+// running in its goroutine after the timeout but its result is discarded.
+func ruleWithTimeout(rule func(reflect.Value) (bool, int), timeout time.Duration) func(reflect.Value) (bool, int) {
+	type ruleResult struct {
+		ok          bool
+		failureFlag int
+		panicked    interface{}
+	}
+	return func(value reflect.Value) (bool, int) {
+		done := make(chan ruleResult, 1)
+		go func() {
+			// panic is passed to the calling goroutine so it can be recovered there
+			defer func() {
+				if r := recover(); r != nil {
+					done <- ruleResult{panicked: r}
+				}
+			}()
+			ok, failureFlag := rule(value)
+			done <- ruleResult{ok: ok, failureFlag: failureFlag}
+		}()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case r := <-done:
+			if r.panicked != nil {
+				panic(r.panicked)
+			}
+			return r.ok, r.failureFlag
+		case <-timer.C:
+			return false, FailTimeout
+		}
+	}
+}
+
+// countDecimals returns number of fractional digits in the shortest decimal representation of f, so that eg.
+// 0.1 stored as float64 has 1 decimal place and not the many digits of its binary approximation.
+func countDecimals(f float64, bitSize int) int {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	formatted := strconv.FormatFloat(f, 'f', -1, bitSize)
+	dot := strings.Index(formatted, ".")
+	if dot == -1 {
+		return 0
+	}
+	return len(formatted) - dot - 1
+}
+
+// isFileMode returns true when int value is a valid unix permission mode between 0 and 0777.
+func isFileMode(value reflect.Value) bool {
+	switch {
+	case isUint(value.Kind()):
+		return value.Uint() <= 0777
+	case isNotInt(value.Kind()):
+		return value.Int() >= 0 && value.Int() <= 0777
+	}
+	return false
+}
+
+// isURL returns true when s is an absolute http or https URL.
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isPasswordCompliant returns true when s meets requirements of policy.
+func isPasswordCompliant(s string, policy *PasswordPolicy) bool {
+	if utf8.RuneCountInString(s) < policy.MinLen {
+		return false
+	}
+	hasUpper, hasDigit, hasSpecial := false, false, false
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+	return (hasUpper || !policy.RequireUpper) && (hasDigit || !policy.RequireDigit) && (hasSpecial || !policy.RequireSpecial)
+}
+
+// hasNumericRules returns true when validation has rules that can only be checked against a number.
+func hasNumericRules(validation *FieldValidation) bool {
+	return validation.valMin != 0 || validation.valMax != 0 || len(validation.valIn) > 0 || validation.powerOf > 0 ||
+		validation.flags&(ValMinNotNil|ValMaxNotNil|Positive|Negative|NonNegative|NonPositive) > 0 ||
+		validation.gt != nil || validation.gte != nil || validation.lt != nil || validation.lte != nil
+}
+
+// parseNumber returns value of int64 or, when s is not an integer, float64 parsed from s.
+func parseNumber(s string) (reflect.Value, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return reflect.ValueOf(i), true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return reflect.ValueOf(f), true
+	}
+	return reflect.Value{}, false
+}
+
+// parseDefault returns value of type t parsed from s. Only strings and numbers can have a default.
+func parseDefault(s string, t reflect.Type) (reflect.Value, bool) {
+	if t == nil {
+		return reflect.Value{}, false
+	}
+	d := reflect.New(t).Elem()
+	switch k := t.Kind(); {
+	case isNotString(k):
+		d.SetString(s)
+	case isUint(k):
+		u, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		d.SetUint(u)
+	case isNotInt(k):
+		i, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		d.SetInt(i)
+	case isFloat(k):
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		d.SetFloat(f)
+	default:
+		return reflect.Value{}, false
+	}
+	return d, true
+}
+
+// applyDefault sets field to d when it has zero value. Nil pointer is set to a pointer to d.
+func applyDefault(field reflect.Value, d reflect.Value) {
+	if !field.CanSet() {
+		return
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			p := reflect.New(field.Type().Elem())
+			p.Elem().Set(d)
+			field.Set(p)
+		}
+		return
+	}
+	if field.IsZero() {
+		field.Set(d)
+	}
+}
+
+// isIP returns true when s is an IP address of version required by IPv4 or IPv6 flag, or of any version.
+func isIP(s string, flags int64) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	if flags&IPv4 > 0 && ip.To4() == nil {
+		return false
+	}
+	if flags&IPv6 > 0 && ip.To4() != nil {
+		return false
+	}
+	return true
+}
+
+// isOneOf returns true when string or int value is one of values.
+func isOneOf(value reflect.Value, values []string) bool {
+	var str string
+	switch {
+	case isNotString(value.Kind()):
+		str = value.String()
+	case isUint(value.Kind()):
+		str = strconv.FormatUint(value.Uint(), 10)
+	case isNotInt(value.Kind()):
+		str = strconv.FormatInt(value.Int(), 10)
+	default:
+		return false
+	}
+	for _, v := range values {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
+
+// numberValue returns value of int, uint or float as float64. Second returned value is false for other kinds.
+func numberValue(value reflect.Value) (float64, bool) {
+	switch {
+	case isUint(value.Kind()):
+		return float64(value.Uint()), true
+	case isNotInt(value.Kind()):
+		return float64(value.Int()), true
+	case isFloat(value.Kind()):
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// isPowerOf returns true when value is an int that is a power of base. Non-positive values are never a power.
+func isPowerOf(value reflect.Value, base int64) bool {
+	var n uint64
+	switch {
+	case isUint(value.Kind()):
+		n = value.Uint()
+	case isNotInt(value.Kind()):
+		if value.Int() <= 0 {
+			return false
+		}
+		n = uint64(value.Int())
+	default:
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+	if base > 1 {
+		for n%uint64(base) == 0 {
+			n = n / uint64(base)
+		}
+	}
+	return n == 1
+}
+
+func validateSign(value reflect.Value, validation *FieldValidation) (bool, int) {
+	var positive, negative, nonNegative, nonPositive bool
+	switch {
+	case isFloat(value.Kind()):
+		f := value.Float()
+		positive, negative, nonNegative, nonPositive = f > 0, f < 0, f >= 0, f <= 0
+	case isUint(value.Kind()):
+		u := value.Uint()
+		positive, negative, nonNegative, nonPositive = u > 0, false, true, u == 0
+	case isNotInt(value.Kind()):
+		i := value.Int()
+		positive, negative, nonNegative, nonPositive = i > 0, i < 0, i >= 0, i <= 0
+	default:
+		return true, 0
+	}
+
+	failureFlags := 0
+	if validation.flags&Positive > 0 && !positive {
+		failureFlags = failureFlags | FailPositive
+	}
+	if validation.flags&Negative > 0 && !negative {
+		failureFlags = failureFlags | FailNegative
+	}
+	if validation.flags&NonNegative > 0 && !nonNegative {
+		failureFlags = failureFlags | FailNonNegative
+	}
+	if validation.flags&NonPositive > 0 && !nonPositive {
+		failureFlags = failureFlags | FailNonPositive
+	}
+	return failureFlags == 0, failureFlags
+}
+
+// keywordFlags maps tag keywords without a value to flags they set
+var keywordFlags = map[string]int64{
+	"req":          Required,
+	"email":        Email,
+	"positive":     Positive,
+	"negative":     Negative,
+	"nonnegative":  NonNegative,
+	"nonpositive":  NonPositive,
+	"bcrypt":       Bcrypt,
+	"weekday":      Weekday,
+	"filemode":     FileMode,
+	"optional":     Optional,
+	"color":        Color,
+	"notblank":     NotBlank,
+	"url":          URL,
+	"uuid":         UUID,
+	"mustbe:true":  MustBeTrue,
+	"mustbe:false": MustBeFalse,
+	"alpha":        Alpha,
+	"numeric":      Numeric,
+	"alphanumeric": AlphaNumeric,
+	"omitempty":    OmitEmpty,
+	"ip":           IP,
+	"ipv4":         IPv4,
+	"ipv6":         IPv6,
+	"cidr":         CIDR,
+	"password":     Password,
+	"hexcolor":     HexColor,
+	"base64":       Base64,
+	"hex":          Hex,
+}
+
+// valueKeywords are tag keywords followed by ":" and a value. Since tag tokens are separated with spaces, a value
+// cannot contain a space, eg. "excludes: " or "startswith:Mr. " does not work, unless ValidationOptions.TokenSeparator
+// is set to something else.
+var valueKeywords = []string{"lenmin", "lenmax", "valmin", "valmax", "regexp", "regexpgroup", "unicodeclass", "lenin", "valin", "maxdecimals", "dayofweek", "formatfield", "leneqfield", "elem", "reqwith", "powerof", "oneof", "custom", "bytemin", "bytemax", "mapval", "before", "after", "eqfield", "nefield", "contains", "excludes", "gt", "gte", "lt", "lte", "min", "max", "len", "startswith", "endswith", "datetime", "default"}
+
+// dateTimeLayouts maps names of layouts from time package that can be used with datetime rule, eg. "datetime:RFC1123",
+// which is the only way to use a layout containing spaces
+var dateTimeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+}
+
+// compileRegexp returns compiled regular expression for pattern, compiling it only once. Error is returned when
+// pattern is invalid.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Store(pattern, re)
+	return re, nil
+}
+
+// parseTimeBound parses value of before and after rules, which is either RFC 3339 time or a date, eg. "2020-01-01".
+func parseTimeBound(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// boundKeyword returns keyword that min or max rule means for type t: lenmin or lenmax for strings, slices, arrays
+// and maps, and valmin or valmax for numbers. Empty string is returned for other types.
+// timeValue returns time held by value. Value of unexported field cannot be turned into interface, so it is read
+// from its address instead. It returns false when value is not time.Time or cannot be read.
+func timeValue(value reflect.Value) (time.Time, bool) {
+	if value.Type() != timeType {
+		return time.Time{}, false
+	}
+	if value.CanInterface() {
+		return value.Interface().(time.Time), true
+	}
+	if value.CanAddr() {
+		return *(*time.Time)(unsafe.Pointer(value.UnsafeAddr())), true
+	}
+	return time.Time{}, false
+}
+
+func boundKeyword(keyword string, t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	switch k := t.Kind(); {
+	case isNotString(k) || k == reflect.Slice || k == reflect.Array || k == reflect.Map:
+		return "len" + keyword
+	case isNotInt(k) || isFloat(k):
+		return "val" + keyword
+	}
+	return ""
+}
+
+// setValidationFromTag sets validation from tag of field of type t. Type is used to resolve rules which meaning
+// depends on it, eg. min and max. Tokens are separated with tokenSep and keywords from their values with kvSep.
+func setValidationFromTag(v *FieldValidation, tag string, t reflect.Type, tokenSep string, kvSep string) {
+	opts := strings.Split(tag, tokenSep)
+	for _, opt := range opts {
+		if opt == "" {
+			continue
+		}
+		opt = canonicalToken(opt, kvSep)
+		if flag, ok := keywordFlags[opt]; ok {
+			v.flags = v.flags | flag
+			continue
+		}
+		known := false
+		for _, valOpt := range valueKeywords {
+			if strings.HasPrefix(opt, valOpt+":") {
+				known = true
+				val := strings.Replace(opt, valOpt+":", "", 1)
+				if valOpt == "regexp" {
+					re, err := compileRegexp(val)
+					if err != nil {
+						v.invalidRegexp = true
+						v.invalidRules = append(v.invalidRules, opt)
+					}
+					v.regexp = re
+					continue
+				}
+				if valOpt == "regexpgroup" {
+					v.regexpGroup = val
+					continue
+				}
+				if valOpt == "formatfield" {
+					v.formatField = val
+					continue
+				}
+				if valOpt == "leneqfield" {
+					v.lenEqField = val
+					continue
+				}
+				if valOpt == "custom" {
+					v.customRules = append(v.customRules, val)
+					continue
+				}
+				if valOpt == "oneof" {
+					v.oneOf = strings.Split(val, "|")
+					continue
+				}
+				if valOpt == "gt" || valOpt == "gte" || valOpt == "lt" || valOpt == "lte" {
+					f, err := strconv.ParseFloat(val, 64)
+					if err != nil {
+						v.invalidRules = append(v.invalidRules, opt)
+						continue
+					}
+					switch valOpt {
+					case "gt":
+						v.gt = &f
+					case "gte":
+						v.gte = &f
+					case "lt":
+						v.lt = &f
+					case "lte":
+						v.lte = &f
+					}
+					continue
+				}
+				if valOpt == "contains" {
+					v.contains = append(v.contains, val)
+					continue
+				}
+				if valOpt == "excludes" {
+					v.excludes = append(v.excludes, val)
+					continue
+				}
+				if valOpt == "datetime" {
+					v.dateTime = val
+					if layout, ok := dateTimeLayouts[val]; ok {
+						v.dateTime = layout
+					}
+					continue
+				}
+				if valOpt == "default" {
+					if d, ok := parseDefault(val, t); ok {
+						v.defaultValue = d
+					} else {
+						v.invalidRules = append(v.invalidRules, opt)
+					}
+					continue
+				}
+				if valOpt == "startswith" {
+					v.startsWith = val
+					continue
+				}
+				if valOpt == "endswith" {
+					v.endsWith = val
+					continue
+				}
+				if valOpt == "eqfield" {
+					v.eqField = val
+					continue
+				}
+				if valOpt == "nefield" {
+					v.neField = val
+					continue
+				}
+				if valOpt == "reqwith" {
+					v.reqWith = val
+					continue
+				}
+				if valOpt == "elem" {
+					if v.elem == nil {
+						v.elem = &FieldValidation{lenMin: -1, lenMax: -1, maxDecimals: -1, length: -1}
+					}
+					setValidationFromTag(v.elem, val, elemType(t, reflect.Slice, reflect.Array), tokenSep, kvSep)
+					if len(v.elem.invalidRules) > 0 || len(v.elem.unknownRules) > 0 {
+						v.invalidRules = append(v.invalidRules, opt)
+					}
+					continue
+				}
+				if valOpt == "before" || valOpt == "after" {
+					t, err := parseTimeBound(val)
+					if err != nil {
+						v.invalidRules = append(v.invalidRules, opt)
+						continue
+					}
+					if valOpt == "before" {
+						v.before = t
+					} else {
+						v.after = t
+					}
+					continue
+				}
+				if valOpt == "mapval" {
+					if v.mapVal == nil {
+						v.mapVal = &FieldValidation{lenMin: -1, lenMax: -1, maxDecimals: -1, length: -1}
+					}
+					setValidationFromTag(v.mapVal, val, elemType(t, reflect.Map), tokenSep, kvSep)
+					if len(v.mapVal.invalidRules) > 0 || len(v.mapVal.unknownRules) > 0 {
+						v.invalidRules = append(v.invalidRules, opt)
+					}
+					continue
+				}
+				if valOpt == "unicodeclass" {
+					for _, category := range strings.Split(val, ",") {
+						if table, ok := unicode.Categories[category]; ok {
+							v.unicodeClasses = append(v.unicodeClasses, table)
+						} else {
+							v.invalidRules = append(v.invalidRules, opt)
+						}
+					}
+					continue
+				}
+				if valOpt == "lenin" {
+					for _, l := range strings.Split(val, ",") {
+						if i, err := strconv.Atoi(l); err == nil {
+							v.lenIn = append(v.lenIn, i)
+						} else {
+							v.invalidRules = append(v.invalidRules, opt)
+						}
+					}
+					continue
+				}
+				if valOpt == "dayofweek" {
+					for _, d := range strings.Split(val, ",") {
+						if i, err := strconv.Atoi(d); err == nil && i >= 0 && i <= 6 {
+							v.daysOfWeek = append(v.daysOfWeek, time.Weekday(i))
+						} else {
+							v.invalidRules = append(v.invalidRules, opt)
+						}
+					}
+					continue
+				}
+				if valOpt == "valin" {
+					for _, n := range strings.Split(val, ",") {
+						if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+							v.valIn = append(v.valIn, i)
+						} else {
+							v.invalidRules = append(v.invalidRules, opt)
+						}
+					}
+					continue
+				}
+
+				keyword := valOpt
+				if keyword == "min" || keyword == "max" {
+					// bounds of interface are resolved against the value it holds when field is validated
+					if t != nil && t.Kind() == reflect.Interface {
+						v.valueBounds = append(v.valueBounds, opt)
+						continue
+					}
+					keyword = boundKeyword(keyword, t)
+					if keyword == "" {
+						v.invalidRules = append(v.invalidRules, opt)
+						continue
+					}
+				}
+				// bounds of time.Duration can be written as durations, eg. "valmin:5s"
+				if t == durationType && (keyword == "valmin" || keyword == "valmax") {
+					if d, err := time.ParseDuration(val); err == nil {
+						if keyword == "valmin" {
+							v.valMin = int64(d)
+							v.flags = v.flags | ValMinNotNil
+						} else {
+							v.valMax = int64(d)
+							v.flags = v.flags | ValMaxNotNil
+						}
+						continue
+					}
+				}
+				i, err := strconv.Atoi(val)
+				if err != nil {
+					v.invalidRules = append(v.invalidRules, opt)
+					continue
+				}
+				switch keyword {
+				case "lenmin":
+					v.lenMin = i
+				case "lenmax":
+					v.lenMax = i
+				case "valmin":
+					v.valMin = int64(i)
+					if i == 0 {
+						v.flags = v.flags | ValMinNotNil
+					}
+				case "valmax":
+					v.valMax = int64(i)
+					if i == 0 {
+						v.flags = v.flags | ValMaxNotNil
+					}
+				case "maxdecimals":
+					v.maxDecimals = i
+				case "powerof":
+					v.powerOf = int64(i)
+				case "bytemin":
+					v.byteMin = i
+				case "bytemax":
+					v.byteMax = i
+				case "len":
+					v.length = i
+				}
+			}
+		}
+		if !known {
+			v.unknownRules = append(v.unknownRules, opt)
+		}
+	}
+}
+
+// indirectType returns type pointed by t when t is a pointer, and t otherwise.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// elemType returns type of elements of t when t is one of kinds, and nil otherwise.
+func elemType(t reflect.Type, kinds ...reflect.Kind) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for _, k := range kinds {
+		if t.Kind() == k {
+			return indirectType(t.Elem())
+		}
+	}
+	return nil
+}
+
+func isSupportedType(t reflect.Type) bool {
+	k := t.Kind()
+	if isNotInt(k) || isNotString(k) || isFloat(k) || k == reflect.Bool || k == reflect.Slice || k == reflect.Array || k == reflect.Map || k == reflect.Interface || t == timeType {
+		return true
+	}
+	return false
+}
+
+func isNotInt(k reflect.Kind) bool {
+	if k == reflect.Int64 || k == reflect.Int32 || k == reflect.Int16 || k == reflect.Int8 || k == reflect.Int || k == reflect.Uint64 || k == reflect.Uint32 || k == reflect.Uint16 || k == reflect.Uint8 || k == reflect.Uint {
+		return true
+	}
+	return false
+}
+
+func isUint(k reflect.Kind) bool {
+	if k == reflect.Uint64 || k == reflect.Uint32 || k == reflect.Uint16 || k == reflect.Uint8 || k == reflect.Uint {
+		return true
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	if k == reflect.Float64 || k == reflect.Float32 {
+		return true
+	}
+	return false
+}
+
+func isNotString(k reflect.Kind) bool {
+	if k == reflect.String {
+		return true
+	}
+	return false
+}
+
+func isKindCompatible(fieldKind reflect.Kind, valueKind reflect.Kind) bool {
+	switch {
+	case isNotString(fieldKind):
+		return isNotString(valueKind)
+	case isUint(fieldKind):
+		return isUint(valueKind)
+	case isNotInt(fieldKind):
+		return isNotInt(valueKind) && !isUint(valueKind)
+	case isFloat(fieldKind):
+		return isFloat(valueKind)
+	}
+	return fieldKind == valueKind
+}
+
+func isIntInSlice(i int, s []int) bool {
+	for _, v := range s {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+func isInt64InSlice(i int64, s []int64) bool {
+	for _, v := range s {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+func isWeekdayInSlice(d time.Weekday, s []time.Weekday) bool {
+	for _, v := range s {
+		if v == d {
+			return true
+		}
+	}
 	return false
 }
 
@@ -1,8 +1,15 @@
 package structvalidator
 
 import (
+	"encoding/json"
 	"log"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 type Test1 struct {
@@ -44,15 +51,443 @@ type Test4 struct {
 	PrimaryEmail string ``
 }
 
+type Test5 struct {
+	Version string `validation:"regexpgroup:minor" validation_regexp:"^(?P<major>[0-9]+)\\.?(?P<minor>[0-9]*)$"`
+	Build   string `validation:"regexpgroup:nonexistent" validation_regexp:"^[0-9]+$"`
+}
+
+type Test6 struct {
+	PositiveInt      int     `validation:"positive"`
+	NegativeInt      int     `validation:"negative"`
+	NonNegativeInt   int     `validation:"nonnegative"`
+	NonPositiveInt   int     `validation:"nonpositive"`
+	PositiveFloat    float64 `validation:"positive"`
+	NegativeFloat    float32 `validation:"negative"`
+	NonNegativeFloat float64 `validation:"nonnegative"`
+	NonPositiveFloat float64 `validation:"nonpositive"`
+	PositiveUint     uint    `validation:"positive"`
+}
+
+type Test7 struct {
+	Username string `validation:"unicodeclass:L,N"`
+	Nickname string `validation:"unicodeclass:Lu"`
+}
+
+type Test8 struct {
+	Username string `validation:"req nospaces"`
+	Quantity int    `validation:"divisible:3"`
+	Comment  string `validation:"unresolved"`
+}
+
+type Test9 struct {
+	ZipCode   string `validation:"lenin:5,9"`
+	PhoneCode string `validation:"req lenin:2,3"`
+}
+
+type Test11 struct {
+	Email  string `validation:"email"`
+	Phone  string
+	Fax    string
+	Mobile int
+}
+
+type Test12 struct {
+	PasswordHash string `validation:"req bcrypt"`
+	RecoveryHash string `validation:"bcrypt"`
+}
+
+type Test13 struct {
+	Flag  int   `validation:"valin:1,2,4,8"`
+	Level int64 `validation:"valin:-2,-1,0,1,2"`
+}
+
+type Test14 struct {
+	First  string `validation:"slow"`
+	Second string `validation:"slow"`
+	Third  string `validation:"slow lenmin:5"`
+	Fourth string `validation:"slow lenmin:5"`
+}
+
+type Test15 struct {
+	Amount       float64 `validation:"maxdecimals:2"`
+	SmallAmount  float32 `validation:"maxdecimals:2"`
+	AmountString string  `validation:"maxdecimals:2"`
+	Whole        float64 `validation:"maxdecimals:0"`
+}
+
+type Test16 struct {
+	MeetingAt  time.Time `validation:"weekday"`
+	DeliveryAt time.Time `validation:"dayofweek:1,3,5"`
+}
+
+type Test17 struct {
+	ContactType string
+	Contact     string `validation:"formatfield:ContactType"`
+	Backup      string `validation:"formatfield:BackupType"`
+}
+
+type Test18 struct {
+	FirstName string `json:"first_name" validation:"req"`
+	LastName  string `json:"last/name,omitempty" validation:"req"`
+	Age       int    `json:"-" validation:"valmin:18"`
+	Email     string `validation:"email"`
+}
+
+type Test19 struct {
+	FileMode uint32 `validation:"filemode"`
+	DirMode  int    `validation:"filemode"`
+}
+
+type Test20 struct {
+	Name     string
+	Nickname string `validation:"optional lenmax:10"`
+	Age      int
+	Email    string `validation:"email"`
+}
+
+type Test21 struct {
+	Username string `validation:"lenmin:3 lenmax:5 unicodeclass:L" validation_regexp:"^[a-z]+$"`
+	Balance  int    `validation:"valmin:10 valin:10,20 positive nonnegative"`
+}
+
+type Test22 struct {
+	Items     []string `validation:"leneqfield:ItemCount"`
+	ItemCount int
+	Tags      []string `validation:"leneqfield:TagCount"`
+	TagCount  uint8
+	Labels    []string `validation:"leneqfield:Missing"`
+}
+
+type Test23 struct {
+	Nickname *string `validation:"lenmin:3"`
+	Name     *string `validation:"req lenmin:3"`
+	Age      *int    `validation:"valmax:150"`
+}
+
+type Test24 struct {
+	Background string `validation:"color"`
+	Foreground string `validation:"req color"`
+}
+
+type Test25 struct {
+	Tags   []string `validation:"lenmin:1 lenmax:3 elem:lenmin:2 elem:lenmax:5"`
+	Scores []int    `validation:"elem:valmin:1 elem:valmax:10"`
+}
+
+type Test26 struct {
+	Title string `validation:"req"`
+	Draft bool
+}
+
+type Test27 struct {
+	Password        string
+	ConfirmPassword string `validation:"reqwith:Password"`
+}
+
+type Test28 struct {
+	Name    string `validation:"req"`
+	Address Test28Address
+	Billing *Test28Address
+}
+
+type Test28Address struct {
+	PostCode string `validation:"req" validation_regexp:"^[0-9][0-9]-[0-9][0-9][0-9]$"`
+	Geo      Test28Geo
+}
+
+type Test28Geo struct {
+	Country string `json:"country" validation:"lenmin:2 lenmax:2"`
+}
+
+type Test29 struct {
+	BufferSize int    `validation:"powerof:2"`
+	Blocks     uint16 `validation:"powerof:10"`
+}
+
+type Test30 struct {
+	Status   string `validation:"oneof:active|inactive|pending"`
+	Priority int    `validation:"oneof:1|2|3"`
+	Role     string `validation:"req oneof:admin|user"`
+}
+
+type Test32 struct {
+	City string `validation:"lenmax:10 bytemax:8"`
+	Code string `validation:"lenmin:3 bytemin:4"`
+}
+
+type Test33 struct {
+	Name    string `validation:"notblank"`
+	Address string `validation:"req notblank lenmin:2"`
+}
+
+type Test34 struct {
+	Level    uint8  `validation:"valmax:200"`
+	Count    uint   `validation:"req"`
+	Offset   uint16 `validation:"valmin:-5 valmax:10"`
+	Negative uint32 `validation:"valmax:-1"`
+}
+
+type Test35 struct {
+	Website  string `validation:"url"`
+	Homepage string `validation:"req url"`
+}
+
+type Test36 struct {
+	ID       string `validation:"req uuid"`
+	ParentID string `validation:"uuid"`
+}
+
+type Test37 struct {
+	Stock  map[string]int    `validation:"lenmin:1 lenmax:3 mapval:valmin:0 mapval:valmax:100"`
+	Labels map[string]string `validation:"mapval:lenmax:5"`
+}
+
+type Test38 struct {
+	CreatedAt time.Time `validation:"req after:2020-01-01 before:2030-01-01T00:00:00Z"`
+	DeletedAt time.Time `validation:"after:2020-01-01"`
+}
+
+type Test39 struct {
+	AcceptedTerms bool `validation:"req mustbe:true"`
+	OptedOut      bool `validation:"mustbe:false"`
+	Newsletter    bool `validation:"req"`
+}
+
+type Test40 struct {
+	Password        string
+	ConfirmPassword string `validation:"eqfield:Password"`
+	OldPassword     string `validation:"nefield:Password"`
+	Min             int
+	Max             int `validation:"nefield:Min"`
+	Total           int `validation:"eqfield:Max"`
+}
+
+type Test41 struct {
+	Name  string `validation:"req"`
+	Phone string
+	Email string `validation:"email"`
+}
+
+// FailPhoneOrEmail uses a bit that no Fail* constant has
+const FailPhoneOrEmail = 1
+
+func (t *Test41) ValidateStruct() map[string]int {
+	failures := map[string]int{}
+	if t.Phone == "" && t.Email == "" {
+		failures["PhoneOrEmail"] = FailPhoneOrEmail
+	}
+	if t.Name == "" || t.Name == "root" {
+		failures["Name"] = FailValIn
+	}
+	return failures
+}
+
+type Test42 struct {
+	FirstName string `validation:"alpha"`
+	PIN       string `validation:"req numeric"`
+	Username  string `validation:"alphanumeric"`
+}
+
+type Test43 struct {
+	Names [3]string `validation:"elem:req"`
+	Codes [2]int    `validation:"elem:valmin:10"`
+}
+
+type Test44 struct {
+	Handle string `validation:"contains:@"`
+	Slug   string `validation:"excludes:_ excludes:/"`
+}
+
+type Test45 struct {
+	Positive    int     `validation:"gt:0"`
+	NonNegative int     `validation:"gte:0"`
+	Negative    int     `validation:"lt:0"`
+	NonPositive int     `validation:"lte:0"`
+	Ratio       float64 `validation:"gt:0 lte:1"`
+	Count       uint8   `validation:"gte:1 lt:10"`
+}
+
+type Test46 struct {
+	Name   string   `validation:"min:5 max:10"`
+	Age    int      `validation:"min:5 max:10"`
+	Tags   []string `validation:"min:1 elem:max:3"`
+	Scores []int    `validation:"elem:min:0"`
+	Rating float64  `validation:"min:1 max:5"`
+}
+
+type Test47 struct {
+	Test47Base
+	*Test47Audit
+	Title string `validation:"req"`
+}
+
+type Test47Base struct {
+	ID string `validation:"req uuid"`
+}
+
+type Test47Audit struct {
+	CreatedBy string `validation:"req"`
+}
+
+type Test48 struct {
+	Name     string
+	Age      int
+	Email    string
+	PostCode string
+	Comment  string `validation:"lenmax:10"`
+}
+
+type Test49 struct {
+	Age      int    `validation:"valmin:abc"`
+	Name     string `validation:"req lenmn:3"`
+	Username string `validation:"req lenmin:3"`
+	Code     string `validation:"regexp:("`
+	Active   bool   `validation:"min:5"`
+}
+
+type Test50 struct {
+	Country string   `validation:"len:2" validation_regexp:"^[A-Z]+$"`
+	Pair    []string `validation:"len:2"`
+}
+
+type Test51 struct {
+	Name  interface{} `validation:"req lenmin:5"`
+	Age   interface{} `validation:"valmin:18"`
+	Other interface{} `validation:"lenmin:5"`
+	Size  interface{} `validation:"min:3 max:5"`
+}
+
+type Test52 struct {
+	Nickname string  `validation:"omitempty lenmin:5" validation_regexp:"^[a-z]+$"`
+	Age      int     `validation:"omitempty valmin:18"`
+	Rate     float64 `validation:"omitempty positive"`
+}
+
+type Test53 struct {
+	Address string `validation:"ip"`
+	Gateway string `validation:"ipv4"`
+	DNS     string `validation:"ipv6"`
+	Subnet  string `validation:"req cidr"`
+}
+
+type Test54 struct {
+	UserID   string `validation:"startswith:usr_"`
+	Filename string `validation:"req endswith:.csv"`
+}
+
+type Test55 struct {
+	Password string `validation:"req password"`
+}
+
+type Test56 struct {
+	Token   []byte `validation:"req lenmin:4 lenmax:8" validation_regexp:"^[a-z0-9]+$"`
+	Contact []byte `validation:"email"`
+}
+
+type Test57 struct {
+	Birthday  string `validation:"datetime:2006-01-02"`
+	CreatedAt string `validation:"req datetime:2006-01-02T15:04"`
+	UpdatedAt string `validation:"datetime:RFC1123"`
+}
+
+type Test58 struct {
+	Age      string      `validation:"req valmin:18 valmax:150"`
+	Quantity json.Number `validation:"positive lte:100"`
+	Note     string      `validation:"lenmax:10"`
+	Size     string      `validation:"valin:36,38,40"`
+}
+
+type Test59 struct {
+	PrimaryEmail   string `validation:"req email"`
+	SecondaryEmail string `validation:"email"`
+	AddressStreet  string `validation:"req"`
+	AddressCity    string `validation:"req"`
+	Name           string `validation:"req"`
+}
+
+type Test60 struct {
+	Country  string  `validation:"req len:2 default:PL"`
+	Currency *string `validation:"req len:3 default:PLN"`
+	Limit    int     `validation:"valmin:1 valmax:100 default:10"`
+	Retries  uint8   `validation:"valmax:5 default:7"`
+}
+
+type Test61 struct {
+	Timeout  time.Duration  `validation:"valmin:5s valmax:1h"`
+	Interval *time.Duration `validation:"min:100ms max:2m30s"`
+}
+
+type Test62 struct {
+	FirstName string   `validation:"req;lenmin=5;lenmax=25"`
+	Title     string   `validation:"startswith=Dr. "`
+	Tags      []string `validation:"lenmax=2;elem=lenmin=3"`
+	Agree     bool     `validation:"mustbe=true"`
+}
+
+type Test63 struct {
+	Background string `validation:"hexcolor"`
+	Foreground string `validation:"req hexcolor"`
+	Avatar     string `validation:"base64"`
+	Checksum   string `validation:"hex"`
+}
+
+type Test64 struct {
+	Name string `validation:"req lenmin:3"`
+	Next *Test64
+}
+
+type Test65 struct {
+	test65Base
+	*test65Audit
+	Name string `validation:"req"`
+}
+
+type test65Base struct {
+	Title string `validation:"req"`
+}
+
+type test65Audit struct {
+	CreatedBy string `validation:"req"`
+}
+
+type Test66 struct {
+	createdAt time.Time `validation:"after:2020-01-01 before:2030-01-01T00:00:00Z"`
+	meetingAt time.Time `validation:"weekday"`
+}
+
+func externalRuleResolver(ruleName string) (func(reflect.Value) (bool, int), bool) {
+	if ruleName == "nospaces" {
+		return func(value reflect.Value) (bool, int) {
+			if strings.Contains(value.String(), " ") {
+				return false, 0
+			}
+			return true, 0
+		}, true
+	}
+	if strings.HasPrefix(ruleName, "divisible:") {
+		divisor, err := strconv.Atoi(strings.TrimPrefix(ruleName, "divisible:"))
+		if err != nil {
+			return nil, false
+		}
+		return func(value reflect.Value) (bool, int) {
+			if value.Int()%int64(divisor) != 0 {
+				return false, 0
+			}
+			return true, 0
+		}, true
+	}
+	return nil, false
+}
+
 func TestWithDefaultValues(t *testing.T) {
 	s := Test1{}
 	expectedBool := false
 	expectedFailedFields := map[string]int{
-		"FirstName": FailEmpty,
-		"LastName":  FailEmpty,
+		"FirstName": FailEmpty | FailLenMin,
+		"LastName":  FailEmpty | FailLenMin,
 		"Age":       FailValMin,
-		"PostCode":  FailEmpty,
-		"Email":     FailEmpty,
+		"PostCode":  FailEmpty | FailRegexp,
+		"Email":     FailEmpty | FailEmail,
 		"Country":   FailRegexp,
 		"BelowZero": FailValMax,
 	}
@@ -302,6 +737,2412 @@ func TestWithOverwrittenValues(t *testing.T) {
 	compare(&s, expectedBool, expectedFailedFields, opts, t)
 }
 
+func TestWithDefaultLenMax(t *testing.T) {
+	s := Test1{
+		FirstName: "Johnny",
+		LastName:  "Smith",
+		Age:       35,
+		PostCode:  "43-155",
+		Email:     "john@example.com",
+		BelowZero: -4,
+		Country:   "GB",
+		County:    "Enfield",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PostCode": FailLenMax,
+		"Email":    FailLenMax,
+	}
+	opts := &ValidationOptions{
+		DefaultLenMax: 5,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.DefaultLenMax = 0
+	compare(&s, true, map[string]int{}, opts, t)
+}
+
+func TestRegexpGroup(t *testing.T) {
+	s := Test5{
+		Version: "1.2",
+		Build:   "123",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Build": FailRegexpGroup,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Version = "1"
+	expectedFailedFields["Version"] = FailRegexpGroup
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Version = "a.2"
+	expectedFailedFields["Version"] = FailRegexp
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSignWithZero(t *testing.T) {
+	s := Test6{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PositiveInt":   FailPositive,
+		"NegativeInt":   FailNegative,
+		"PositiveFloat": FailPositive,
+		"NegativeFloat": FailNegative,
+		"PositiveUint":  FailPositive,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSignWithValid(t *testing.T) {
+	s := Test6{
+		PositiveInt:      1,
+		NegativeInt:      -1,
+		NonNegativeInt:   5,
+		NonPositiveInt:   -5,
+		PositiveFloat:    0.01,
+		NegativeFloat:    -0.01,
+		NonNegativeFloat: 0.5,
+		NonPositiveFloat: -0.5,
+		PositiveUint:     1,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSignWithInvalid(t *testing.T) {
+	s := Test6{
+		PositiveInt:      -1,
+		NegativeInt:      1,
+		NonNegativeInt:   -1,
+		NonPositiveInt:   1,
+		PositiveFloat:    -0.01,
+		NegativeFloat:    0.01,
+		NonNegativeFloat: -0.5,
+		NonPositiveFloat: 0.5,
+		PositiveUint:     1,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PositiveInt":      FailPositive,
+		"NegativeInt":      FailNegative,
+		"NonNegativeInt":   FailNonNegative,
+		"NonPositiveInt":   FailNonPositive,
+		"PositiveFloat":    FailPositive,
+		"NegativeFloat":    FailNegative,
+		"NonNegativeFloat": FailNonNegative,
+		"NonPositiveFloat": FailNonPositive,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithOverwrittenTypedValues(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           35,
+		Price:         0,
+		PostCode:      "43-155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+		County:        "Enfield",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Age":       FailOverwriteType,
+		"LastName":  FailLenMin,
+		"BelowZero": FailValMax,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"Age":      "35",
+			"LastName": "Smithson",
+		},
+		OverwriteFieldValuesTyped: map[string]reflect.Value{
+			"Age":       reflect.ValueOf("35"),
+			"LastName":  reflect.ValueOf("S"),
+			"BelowZero": reflect.ValueOf(int8(4)),
+			"FirstName": reflect.ValueOf("Jonathan"),
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// rules referencing other fields see their typed overwrite values
+	s40 := Test40{
+		ConfirmPassword: "secret",
+		OldPassword:     "old",
+		Max:             1,
+		Total:           1,
+	}
+	opts = &ValidationOptions{
+		OverwriteFieldValuesTyped: map[string]reflect.Value{
+			"Password": reflect.ValueOf("secret"),
+		},
+		RequireAny: [][]string{{"Password"}},
+	}
+	compare(&s40, true, map[string]int{}, opts, t)
+}
+
+func TestUnicodeClass(t *testing.T) {
+	s := Test7{}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test7{
+		Username: "Zażółć123",
+		Nickname: "ŻÓŁW",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test7{
+		Username: "john_doe",
+		Nickname: "Żółw",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailUnicodeClass,
+		"Nickname": FailUnicodeClass,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestExternalRuleResolver(t *testing.T) {
+	s := Test8{
+		Username: "john doe",
+		Quantity: 4,
+		Comment:  "anything",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailExternal,
+		"Quantity": FailExternal,
+	}
+	opts := &ValidationOptions{
+		ExternalRuleResolver: externalRuleResolver,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Username = "johndoe"
+	s.Quantity = 6
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s.Username = "john doe"
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+}
+
+func TestLenIn(t *testing.T) {
+	s := Test9{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PhoneCode": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test9{
+		ZipCode:   "123456789",
+		PhoneCode: "48",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test9{
+		ZipCode:   "123456",
+		PhoneCode: "1",
+	}
+	expectedFailedFields = map[string]int{
+		"ZipCode":   FailLenIn,
+		"PhoneCode": FailLenIn,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestCustomValidatorTimeout(t *testing.T) {
+	s := Test8{
+		Username: "johndoe",
+		Quantity: 3,
+	}
+	opts := &ValidationOptions{
+		ExternalRuleResolver: func(ruleName string) (func(reflect.Value) (bool, int), bool) {
+			if ruleName != "nospaces" {
+				return externalRuleResolver(ruleName)
+			}
+			return func(value reflect.Value) (bool, int) {
+				time.Sleep(200 * time.Millisecond)
+				return true, 0
+			}, true
+		},
+		CustomValidatorTimeout: 20 * time.Millisecond,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailTimeout | FailExternal,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.CustomValidatorTimeout = time.Second
+	compare(&s, true, map[string]int{}, opts, t)
+
+	c := Test31{
+		Username: "john",
+		Nickname: "Johnny",
+	}
+	opts = &ValidationOptions{
+		CustomValidators: map[string]func(reflect.Value) bool{
+			"nocaps": func(value reflect.Value) bool {
+				time.Sleep(200 * time.Millisecond)
+				return true
+			},
+			"nodigits": func(value reflect.Value) bool {
+				return false
+			},
+		},
+		CustomValidatorTimeout: 20 * time.Millisecond,
+	}
+	expectedFailedFields = map[string]int{
+		"Username": FailTimeout,
+		"Nickname": FailTimeout | FailCustom,
+	}
+	start := time.Now()
+	compare(&c, expectedBool, expectedFailedFields, opts, t)
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("Validate took %s where custom validators should time out after 20ms", elapsed)
+	}
+}
+
+func TestWithInvalidValuesAndFieldMatcher(t *testing.T) {
+	s := Test1{
+		FirstName:     "123456789012345678901234567890",
+		LastName:      "b",
+		Age:           15,
+		Price:         0,
+		PostCode:      "AA123",
+		Email:         "invalidEmail",
+		BelowZero:     8,
+		DiscountPrice: 9999,
+		Country:       "Tokelau",
+		County:        "",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"FirstName": FailLenMax,
+		"LastName":  FailLenMin,
+	}
+	opts := &ValidationOptions{
+		FieldMatcher: func(name string) bool {
+			matched, _ := path.Match("*Name", name)
+			return matched
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.RestrictFields = map[string]bool{
+		"LastName": true,
+		"Age":      true,
+	}
+	expectedFailedFields = map[string]int{
+		"LastName": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOnValidationPanic(t *testing.T) {
+	s := Test8{
+		Username: "johndoe",
+		Quantity: 3,
+	}
+	panicked := map[string]interface{}{}
+	opts := &ValidationOptions{
+		ExternalRuleResolver: func(ruleName string) (func(reflect.Value) (bool, int), bool) {
+			if ruleName != "nospaces" {
+				return externalRuleResolver(ruleName)
+			}
+			return func(value reflect.Value) (bool, int) {
+				panic("nospaces is broken")
+			}, true
+		},
+		OnValidationPanic: func(field string, r interface{}) {
+			panicked[field] = r
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailPanic,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+	if len(panicked) != 1 || panicked["Username"] != "nospaces is broken" {
+		t.Fatalf("OnValidationPanic was not called with the panic value")
+	}
+
+	opts.CustomValidatorTimeout = time.Second
+	opts.OnValidationPanic = nil
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequireAny(t *testing.T) {
+	opts := &ValidationOptions{
+		RequireAny: [][]string{
+			[]string{"Email", "Phone"},
+			[]string{"Fax", "Mobile"},
+		},
+	}
+	s := Test11{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Email":       FailEmail,
+		"Email,Phone": FailRequireAny,
+		"Fax,Mobile":  FailRequireAny,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test11{
+		Phone:  "+48 123 456 789",
+		Mobile: 123456789,
+	}
+	expectedFailedFields = map[string]int{
+		"Email": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	opts := &ValidationOptions{
+		MutuallyExclusive: [][]string{
+			[]string{"Phone", "Fax", "Mobile"},
+		},
+	}
+	s := Test11{
+		Email: "john@example.com",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s.Fax = "+48 123 456 789"
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s.Mobile = 123456789
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Phone,Fax,Mobile": FailMutuallyExclusive,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithFieldRegexps(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           35,
+		Price:         0,
+		PostCode:      "43155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GBR",
+		County:        "Enfield",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PostCode": FailRegexp,
+		"County":   FailRegexp,
+	}
+	opts := &ValidationOptions{
+		FieldRegexps: map[string]*regexp.Regexp{
+			"Country": regexp.MustCompile("^[A-Z]{3}$"),
+			"County":  regexp.MustCompile("^[A-Z]+$"),
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBcrypt(t *testing.T) {
+	s := Test12{
+		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test12{
+		PasswordHash: "MySecretPassword1",
+		RecoveryHash: "$2b$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhW",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PasswordHash": FailBcrypt,
+		"RecoveryHash": FailBcrypt,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestValIn(t *testing.T) {
+	s := Test13{
+		Flag:  4,
+		Level: -2,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test13{
+		Flag:  3,
+		Level: -3,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Flag":  FailValIn,
+		"Level": FailValIn,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test13{}
+	expectedFailedFields = map[string]int{
+		"Flag": FailValIn,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestWithFieldAliases(t *testing.T) {
+	s := Test11{
+		Email:  "invalidEmail",
+		Phone:  "+48 123 456 789",
+		Mobile: 123456789,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"email_address":       FailEmail,
+		"email_address,phone": FailMutuallyExclusive,
+	}
+	opts := &ValidationOptions{
+		FieldAliases: map[string]string{
+			"Email": "email_address",
+			"Phone": "phone",
+		},
+		MutuallyExclusive: [][]string{
+			[]string{"Email", "Phone"},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRuleTimeoutBudget(t *testing.T) {
+	s := Test14{
+		First:  "a",
+		Second: "b",
+		Third:  "c",
+		Fourth: "d",
+	}
+	opts := &ValidationOptions{
+		ExternalRuleResolver: func(ruleName string) (func(reflect.Value) (bool, int), bool) {
+			return func(value reflect.Value) (bool, int) {
+				time.Sleep(30 * time.Millisecond)
+				return true, 0
+			}, ruleName == "slow"
+		},
+		RuleTimeoutBudget: 50 * time.Millisecond,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Third": FailBudgetExceeded,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.RuleTimeoutBudget = time.Second
+	expectedFailedFields = map[string]int{
+		"Third":  FailLenMin,
+		"Fourth": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDedupeFailures(t *testing.T) {
+	s := Test11{
+		Phone: "+48 123 456 789",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Email": FailEmail | FailRequireAny,
+	}
+	opts := &ValidationOptions{
+		RequireAny: [][]string{
+			[]string{"Email"},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.DisableDedupeFailures = true
+	expectedFailedFields = map[string]int{
+		"Email": FailRequireAny,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMaxDecimals(t *testing.T) {
+	s := Test15{
+		Amount:       19.99,
+		SmallAmount:  0.1,
+		AmountString: "1234.50",
+		Whole:        12,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test15{
+		Amount:       19.999,
+		SmallAmount:  0.125,
+		AmountString: "1234.505",
+		Whole:        12.5,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Amount":       FailMaxDecimals,
+		"SmallAmount":  FailMaxDecimals,
+		"AmountString": FailMaxDecimals,
+		"Whole":        FailMaxDecimals,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test15{
+		AmountString: "12,50",
+	}
+	expectedFailedFields = map[string]int{
+		"AmountString": FailMaxDecimals,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestWithShortCircuitFieldFunc(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           15,
+		Price:         0,
+		PostCode:      "AA123",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+		County:        "Enfield",
+	}
+	opts := &ValidationOptions{
+		ShortCircuitFieldFunc: func(field string, obj interface{}) bool {
+			// post code is only validated for Poland
+			return field == "PostCode" && obj.(*Test1).Country != "PL"
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Age": FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Country = "PL"
+	expectedFailedFields["PostCode"] = FailRegexp
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWeekday(t *testing.T) {
+	s := Test16{}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	// 2024-04-15 is Monday
+	s = Test16{
+		MeetingAt:  time.Date(2024, 4, 19, 10, 0, 0, 0, time.UTC),
+		DeliveryAt: time.Date(2024, 4, 17, 10, 0, 0, 0, time.UTC),
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test16{
+		MeetingAt:  time.Date(2024, 4, 20, 10, 0, 0, 0, time.UTC),
+		DeliveryAt: time.Date(2024, 4, 16, 10, 0, 0, 0, time.UTC),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"MeetingAt":  FailWeekday,
+		"DeliveryAt": FailWeekday,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestFormatField(t *testing.T) {
+	s := Test17{
+		ContactType: "email",
+		Contact:     "john@example.com",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Backup": FailFormatField,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.Contact = "+48 123 456 789"
+	expectedFailedFields["Contact"] = FailEmail
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.ContactType = "phone"
+	expectedFailedFields["Contact"] = FailFormatField
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.ContactType = ""
+	delete(expectedFailedFields, "Contact")
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestEmitJSONPointer(t *testing.T) {
+	s := Test18{
+		Age:   15,
+		Email: "invalidEmail",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"/first_name":  FailEmpty,
+		"/last~1name":  FailEmpty,
+		"/Age":         FailValMin,
+		"/e-mail":      FailEmail,
+		"/Age,/e-mail": FailMutuallyExclusive,
+	}
+	opts := &ValidationOptions{
+		EmitJSONPointer: true,
+		FieldAliases: map[string]string{
+			"Email": "e-mail",
+		},
+		MutuallyExclusive: [][]string{
+			[]string{"Age", "Email"},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateField(t *testing.T) {
+	s := Test1{
+		FirstName: "John",
+		LastName:  "Smith",
+		Age:       15,
+	}
+	opts := &ValidationOptions{}
+	_, failedFields := Validate(&s, opts)
+	for _, name := range []string{"FirstName", "LastName", "Age", "Email"} {
+		valid, failureFlags := ValidateField(&s, name, opts)
+		if valid != (failedFields[name] == 0) || failureFlags != failedFields[name] {
+			t.Fatalf("ValidateField returned %v %d where Validate returned %d for %s", valid, failureFlags, failedFields[name], name)
+		}
+	}
+
+	valid, failureFlags := ValidateField(&s, "FirstName", &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"FirstName": map[string]string{
+				"validation": "req lenmin:2",
+			},
+		},
+	})
+	if !valid || failureFlags != 0 {
+		t.Fatalf("ValidateField did not use overwritten tag")
+	}
+
+	valid, failureFlags = ValidateField(&s, "FirstName", &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"FirstName": "",
+		},
+	})
+	if valid || failureFlags != FailEmpty|FailLenMin {
+		t.Fatalf("ValidateField did not use overwritten value")
+	}
+
+	valid, failureFlags = ValidateField(&Test2{FirstName: "John"}, "FirstName", &ValidationOptions{
+		OverwriteTagName: "mytag",
+	})
+	if valid || failureFlags != FailLenMin {
+		t.Fatalf("ValidateField did not use overwritten tag name")
+	}
+
+	valid, failureFlags = ValidateField(&s, "MiddleName", opts)
+	if valid || failureFlags != 0 {
+		t.Fatalf("ValidateField returned invalid result for field that does not exist")
+	}
+
+	// field promoted from nil pointer to embedded struct is empty
+	valid, failureFlags = ValidateField(&Test47{}, "CreatedBy", &ValidationOptions{ApplyDefaults: true})
+	if valid || failureFlags != FailEmpty {
+		t.Fatalf("ValidateField returned %v %d for field of nil embedded struct", valid, failureFlags)
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	s := Test19{
+		FileMode: 0644,
+		DirMode:  0755,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test19{
+		FileMode: 01000,
+		DirMode:  -1,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"FileMode": FailFileMode,
+		"DirMode":  FailFileMode,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestRequiredByDefault(t *testing.T) {
+	s := Test20{
+		Email: "john@example.com",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name": FailEmpty,
+		"Age":  FailZero,
+	}
+	opts := &ValidationOptions{
+		RequiredByDefault: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Email = ""
+	expectedFailedFields["Email"] = FailEmpty | FailEmail
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test20{
+		Name:  "John",
+		Age:   35,
+		Email: "john@example.com",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s = Test20{}
+	expectedFailedFields = map[string]int{
+		"Email": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	f := Test15{AmountString: "1.5", SmallAmount: 0.5}
+	expectedFailedFields = map[string]int{
+		"Amount": FailZero,
+		"Whole":  FailZero,
+	}
+	compare(&f, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestAllFailures(t *testing.T) {
+	s := Test21{
+		Username: "John_Smith",
+		Balance:  -5,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Username": FailLenMax | FailRegexp | FailUnicodeClass,
+		"Balance":  FailValMin | FailValIn | FailPositive | FailNonNegative,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	_, failedFields := Validate(&s, &ValidationOptions{})
+	if !HasFailure(failedFields["Username"], FailRegexp) || HasFailure(failedFields["Username"], FailLenMin) {
+		t.Fatalf("HasFailure returned invalid value")
+	}
+}
+
+func TestLenEqField(t *testing.T) {
+	s := Test22{
+		Items:     []string{"a", "b"},
+		ItemCount: 2,
+		TagCount:  0,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Labels": FailLenEqField,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test22{
+		Items:     []string{"a", "b", "c"},
+		ItemCount: 2,
+		Tags:      []string{"x"},
+		TagCount:  2,
+	}
+	expectedFailedFields = map[string]int{
+		"Items":  FailLenEqField,
+		"Tags":   FailLenEqField,
+		"Labels": FailLenEqField,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Items": true,
+		},
+		OverwriteFieldValues: map[string]interface{}{
+			"ItemCount": 3,
+		},
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+}
+
+func TestPointerFields(t *testing.T) {
+	s := Test23{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	name := "Jo"
+	age := 200
+	s = Test23{
+		Name: &name,
+		Age:  &age,
+	}
+	expectedFailedFields = map[string]int{
+		"Name": FailLenMin,
+		"Age":  FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	name = "John"
+	age = 35
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+}
+
+func TestColor(t *testing.T) {
+	for _, color := range []string{"#fff", "#1A2b3C", "rgb(255, 0, 12)", "rgb(0,0,0)", "navy", "Red"} {
+		s := Test24{
+			Foreground: color,
+		}
+		compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+	}
+
+	for _, color := range []string{"#ffff", "123456", "rgb(256, 0, 0)", "rgb(1, 2)", "bluish"} {
+		s := Test24{
+			Background: color,
+			Foreground: "#000",
+		}
+		expectedFailedFields := map[string]int{
+			"Background": FailColor,
+		}
+		compare(&s, false, expectedFailedFields, &ValidationOptions{}, t)
+	}
+
+	s := Test24{}
+	expectedFailedFields := map[string]int{
+		"Foreground": FailEmpty,
+	}
+	compare(&s, false, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestSliceFields(t *testing.T) {
+	s := Test25{
+		Tags:   []string{"go", "tags"},
+		Scores: []int{1, 10},
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test25{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Tags": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test25{
+		Tags:   []string{"a", "bb", "cc", "dd"},
+		Scores: []int{5, 11},
+	}
+	expectedFailedFields = map[string]int{
+		"Tags":   FailLenMax | FailElem,
+		"Scores": FailElem,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test25{
+		Tags: []string{"go", "toolong"},
+	}
+	expectedFailedFields = map[string]int{
+		"Tags": FailElem,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test25{
+		Tags:   []string{"go"},
+		Scores: []int{},
+	}
+	expectedFailedFields = map[string]int{
+		"Scores": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{RequiredByDefault: true}, t)
+}
+
+func TestSkipValidationTag(t *testing.T) {
+	s := Test26{
+		Draft: true,
+	}
+	opts := &ValidationOptions{
+		SkipValidationTag: "Draft",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s.Draft = false
+	expectedFailedFields := map[string]int{
+		"Title": FailEmpty,
+	}
+	compare(&s, false, expectedFailedFields, opts, t)
+}
+
+func TestReqWith(t *testing.T) {
+	s := Test27{}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s.Password = "secret"
+	expectedFailedFields := map[string]int{
+		"ConfirmPassword": FailEmpty,
+	}
+	compare(&s, false, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.ConfirmPassword = "secret"
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+}
+
+func TestRecursive(t *testing.T) {
+	s := Test28{
+		Name: "John",
+		Address: Test28Address{
+			PostCode: "12-345",
+			Geo: Test28Geo{
+				Country: "Poland",
+			},
+		},
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		Recursive: true,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Address.Geo.Country": FailLenMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Billing = &Test28Address{
+		PostCode: "12345",
+		Geo: Test28Geo{
+			Country: "PL",
+		},
+	}
+	expectedFailedFields["Billing.PostCode"] = FailRegexp
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.EmitJSONPointer = true
+	expectedFailedFields = map[string]int{
+		"/Address/Geo/country": FailLenMax,
+		"/Billing/PostCode":    FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRecursiveSelectFields(t *testing.T) {
+	s := Test28{
+		Address: Test28Address{
+			PostCode: "12345",
+			Geo: Test28Geo{
+				Country: "Poland",
+			},
+		},
+		Billing: &Test28Address{
+			PostCode: "12345",
+		},
+	}
+	opts := &ValidationOptions{
+		Recursive: true,
+		RestrictFields: map[string]bool{
+			"Address": true,
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Address.PostCode":    FailRegexp,
+		"Address.Geo.Country": FailLenMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.RestrictFields = map[string]bool{
+		"Address.PostCode": true,
+	}
+	expectedFailedFields = map[string]int{
+		"Address.PostCode": FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// options with names of fields of nested structs apply only to the field with qualified name
+	opts = &ValidationOptions{
+		Recursive: true,
+		SkipFields: map[string]bool{
+			"Name":             true,
+			"Address.PostCode": true,
+			"Address.Geo":      true,
+		},
+		OverwriteFieldTags: map[string]map[string]string{
+			"Billing.PostCode": {"validation": "lenmin:6"},
+		},
+		FieldRegexps: map[string]*regexp.Regexp{
+			"PostCode": regexp.MustCompile("^[0-9]+$"),
+		},
+	}
+	expectedFailedFields = map[string]int{
+		"Billing.PostCode":    FailLenMin | FailRegexp,
+		"Billing.Geo.Country": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPowerOf(t *testing.T) {
+	for _, size := range []int{1, 2, 8, 1024} {
+		s := Test29{
+			BufferSize: size,
+			Blocks:     100,
+		}
+		compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+	}
+
+	for _, size := range []int{6, 0, -2} {
+		s := Test29{
+			BufferSize: size,
+			Blocks:     101,
+		}
+		expectedFailedFields := map[string]int{
+			"BufferSize": FailPowerOf,
+			"Blocks":     FailPowerOf,
+		}
+		compare(&s, false, expectedFailedFields, &ValidationOptions{}, t)
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	s := Test30{
+		Status:   "pending",
+		Priority: 2,
+		Role:     "admin",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test30{
+		Status:   "deleted",
+		Priority: 4,
+		Role:     "root",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Status":   FailOneOf,
+		"Priority": FailOneOf,
+		"Role":     FailOneOf,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test30{}
+	expectedFailedFields = map[string]int{
+		"Role": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestByteLength(t *testing.T) {
+	s := Test32{
+		City: "Łódź",
+		Code: "Łód",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test32{
+		City: "Żółć-Łódź",
+		Code: "abc",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"City": FailByteMax,
+		"Code": FailByteMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestNotBlank(t *testing.T) {
+	s := Test33{
+		Name:    " John ",
+		Address: "Main St",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test33{
+		Name:    "   ",
+		Address: "\t\n",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name":    FailBlank,
+		"Address": FailBlank,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test33{}
+	expectedFailedFields = map[string]int{
+		"Name":    FailBlank,
+		"Address": FailEmpty | FailBlank | FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestUintValues(t *testing.T) {
+	s := Test34{
+		Level:  200,
+		Count:  1,
+		Offset: 0,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Negative": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test34{
+		Level:  255,
+		Offset: 11,
+	}
+	expectedFailedFields = map[string]int{
+		"Level":    FailValMax,
+		"Count":    FailZero,
+		"Offset":   FailValMax,
+		"Negative": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestRegexpCache(t *testing.T) {
+	s := Test1{
+		PostCode: "AA123",
+		Country:  "PL",
+	}
+	_, expectedFailedFields := Validate(&s, &ValidationOptions{})
+	for i := 0; i < 3; i++ {
+		compare(&s, false, expectedFailedFields, &ValidationOptions{}, t)
+	}
+	re1, _ := compileRegexp("^[A-Z][A-Z]$")
+	re2, _ := compileRegexp("^[A-Z][A-Z]$")
+	if re1 != re2 {
+		t.Fatalf("compileRegexp compiled the same pattern twice")
+	}
+	if _, err := compileRegexp("("); err == nil {
+		t.Fatalf("compileRegexp returned no error for invalid pattern")
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	s := Test1{
+		FirstName: "Johnny",
+		LastName:  "Smith",
+		Age:       35,
+		Price:     100,
+		PostCode:  "00-123",
+		Email:     "john@example.com",
+		BelowZero: -4,
+		Country:   "PL",
+	}
+	opts := &ValidationOptions{}
+	for i := 0; i < b.N; i++ {
+		Validate(&s, opts)
+	}
+}
+
+func TestURL(t *testing.T) {
+	s := Test35{
+		Website:  "https://example.com/path?q=1",
+		Homepage: "http://example.com",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test35{
+		Website:  "example.com",
+		Homepage: "ftp://example.com",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Website":  FailURL,
+		"Homepage": FailURL,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test35{}
+	expectedFailedFields = map[string]int{
+		"Homepage": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	c := Test17{
+		ContactType: "url",
+		Contact:     "not a url",
+	}
+	expectedFailedFields = map[string]int{
+		"Contact": FailURL,
+		"Backup":  FailFormatField,
+	}
+	compare(&c, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestUUID(t *testing.T) {
+	s := Test36{
+		ID:       "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		ParentID: "F47AC10B-58CC-4372-A567-0E02B2C3D479",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test36{
+		ID:       "f47ac10b58cc4372a5670e02b2c3d479",
+		ParentID: "f47ac10b-58cc-4372-a567-0e02b2c3d47z",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"ID":       FailUUID,
+		"ParentID": FailUUID,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test36{}
+	expectedFailedFields = map[string]int{
+		"ID": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestMapFields(t *testing.T) {
+	s := Test37{
+		Stock:  map[string]int{"apple": 0, "pear": 100},
+		Labels: map[string]string{"color": "red"},
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test37{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Stock": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test37{
+		Stock:  map[string]int{"apple": 1, "pear": 2, "plum": 3, "kiwi": 101},
+		Labels: map[string]string{"color": "yellow"},
+	}
+	expectedFailedFields = map[string]int{
+		"Stock":  FailLenMax | FailMapVal,
+		"Labels": FailMapVal,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test37{}
+	expectedFailedFields = map[string]int{
+		"Stock":  FailEmpty | FailLenMin,
+		"Labels": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{RequiredByDefault: true}, t)
+}
+
+func TestTimeBounds(t *testing.T) {
+	s := Test38{
+		CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s.CreatedAt = time.Date(2019, 12, 31, 23, 59, 0, 0, time.UTC)
+	s.DeletedAt = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"CreatedAt": FailAfter,
+		"DeletedAt": FailAfter,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test38{
+		CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	expectedFailedFields = map[string]int{
+		"CreatedAt": FailBefore,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test38{}
+	expectedFailedFields = map[string]int{
+		"CreatedAt": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestBoolMustBe(t *testing.T) {
+	s := Test39{
+		AcceptedTerms: true,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test39{
+		OptedOut: true,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"AcceptedTerms": FailBool,
+		"OptedOut":      FailBool,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestEqField(t *testing.T) {
+	s := Test40{
+		Password:        "secret",
+		ConfirmPassword: "secret",
+		OldPassword:     "password",
+		Min:             1,
+		Max:             10,
+		Total:           10,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test40{
+		Password:        "secret",
+		ConfirmPassword: "secret1",
+		OldPassword:     "secret",
+		Min:             10,
+		Max:             10,
+		Total:           5,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"ConfirmPassword": FailEqField,
+		"OldPassword":     FailNeField,
+		"Max":             FailNeField,
+		"Total":           FailEqField,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestStructValidator(t *testing.T) {
+	s := Test41{
+		Name:  "John",
+		Email: "john@example.com",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test41{
+		Email: "john@example.com",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name": FailEmpty | FailValIn,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test41{
+		Name:  "John",
+		Phone: "123456789",
+	}
+	expectedFailedFields = map[string]int{
+		"Email": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test41{
+		Name: "John",
+	}
+	expectedFailedFields = map[string]int{
+		"Email":        FailEmail,
+		"PhoneOrEmail": FailPhoneOrEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test41{
+		Name:  "root",
+		Email: "invalid",
+	}
+	expectedFailedFields = map[string]int{
+		"Name":  FailValIn,
+		"Email": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestCharset(t *testing.T) {
+	s := Test42{
+		FirstName: "John",
+		PIN:       "0123",
+		Username:  "john85",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test42{
+		FirstName: "John2",
+		PIN:       "12a4",
+		Username:  "john_85",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"FirstName": FailCharset,
+		"PIN":       FailCharset,
+		"Username":  FailCharset,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test42{}
+	expectedFailedFields = map[string]int{
+		"PIN": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestSkipFields(t *testing.T) {
+	s := Test1{
+		FirstName: "Johnny",
+		LastName:  "Smith",
+		Age:       15,
+	}
+	opts := &ValidationOptions{
+		SkipFields: map[string]bool{
+			"Age":       true,
+			"PostCode":  true,
+			"Email":     true,
+			"BelowZero": true,
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Country": FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.RestrictFields = map[string]bool{
+		"FirstName": true,
+		"Age":       true,
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+}
+
+func TestArrayFields(t *testing.T) {
+	s := Test43{
+		Names: [3]string{"John", "Jane", "Jack"},
+		Codes: [2]int{10, 20},
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test43{
+		Names: [3]string{"John", "", "Jack"},
+		Codes: [2]int{10, 5},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Names": FailElem,
+		"Codes": FailElem,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestContainsExcludes(t *testing.T) {
+	s := Test44{
+		Handle: "@john",
+		Slug:   "john-smith",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test44{
+		Handle: "john",
+		Slug:   "john/smith",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Handle": FailContains,
+		"Slug":   FailExcludes,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestUseJSONNames(t *testing.T) {
+	s := Test18{
+		Age:   15,
+		Email: "invalid",
+	}
+	opts := &ValidationOptions{
+		UseJSONNames: true,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"first_name": FailEmpty,
+		"last/name":  FailEmpty,
+		"Age":        FailValMin,
+		"Email":      FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	r := Test28{
+		Address: Test28Address{
+			PostCode: "12-345",
+			Geo: Test28Geo{
+				Country: "Poland",
+			},
+		},
+	}
+	opts.Recursive = true
+	expectedFailedFields = map[string]int{
+		"Name":                FailEmpty,
+		"Address.Geo.country": FailLenMax,
+	}
+	compare(&r, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestComparisons(t *testing.T) {
+	s := Test45{
+		Positive:    1,
+		NonNegative: 0,
+		Negative:    -1,
+		NonPositive: 0,
+		Ratio:       1,
+		Count:       9,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test45{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Positive": FailGt,
+		"Negative": FailLt,
+		"Ratio":    FailGt,
+		"Count":    FailGte,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test45{
+		Positive:    1,
+		NonNegative: -1,
+		Negative:    -1,
+		NonPositive: 1,
+		Ratio:       1.5,
+		Count:       10,
+	}
+	expectedFailedFields = map[string]int{
+		"NonNegative": FailGte,
+		"NonPositive": FailLte,
+		"Ratio":       FailLte,
+		"Count":       FailLt,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestEmailPattern(t *testing.T) {
+	s := Test18{
+		FirstName: "John",
+		LastName:  "Smith",
+		Age:       35,
+		Email:     "john+news@example.xn--p1ai.",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Email": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		EmailPattern: `^[^@\s]+@[^@\s]+$`,
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s.Email = "john.example.com"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	valid, failedFields, err := ValidateSafe(&s, &ValidationOptions{EmailPattern: "("})
+	if valid || len(failedFields) != 0 || err == nil {
+		t.Fatalf("ValidateSafe with invalid EmailPattern returned %v, %v, %v", valid, failedFields, err)
+	}
+	valid, failedFields = ValidateMap(map[string]interface{}{"Email": "john@example.com"}, map[string]string{"Email": "email"}, &ValidationOptions{EmailPattern: "("})
+	if valid || len(failedFields) != 0 {
+		t.Fatalf("ValidateMap with invalid EmailPattern returned %v, %v", valid, failedFields)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	s := Test46{
+		Name:   "Johnny",
+		Age:    7,
+		Tags:   []string{"go"},
+		Scores: []int{0, 5},
+		Rating: 4.5,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test46{
+		Name:   "John",
+		Age:    11,
+		Tags:   []string{"golang"},
+		Scores: []int{-1},
+		Rating: 5.5,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name":   FailLenMin,
+		"Age":    FailValMax,
+		"Tags":   FailElem,
+		"Scores": FailElem,
+		"Rating": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test46{
+		Name:   "John Smith Jr",
+		Age:    4,
+		Rating: 0.5,
+	}
+	expectedFailedFields = map[string]int{
+		"Name":   FailLenMax,
+		"Age":    FailValMin,
+		"Tags":   FailLenMin,
+		"Rating": FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestValidateEmbedded(t *testing.T) {
+	s := Test47{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Title": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		ValidateEmbedded: true,
+	}
+	expectedFailedFields["ID"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Test47Audit = &Test47Audit{}
+	expectedFailedFields["CreatedBy"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test47{
+		Test47Base: Test47Base{
+			ID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		},
+		Test47Audit: &Test47Audit{
+			CreatedBy: "john",
+		},
+		Title: "Title",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+}
+
+func TestValidateEmbeddedUnexported(t *testing.T) {
+	s := Test65{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	opts := &ValidationOptions{
+		ValidateEmbedded: true,
+	}
+	expectedFailedFields["Title"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.test65Audit = &test65Audit{}
+	expectedFailedFields["CreatedBy"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// fields of embedded struct of unexported type are short-circuited with the struct they are promoted to
+	opts.ShortCircuitFieldFunc = func(field string, obj interface{}) bool {
+		_, ok := obj.(*Test65)
+		return ok && field == "CreatedBy"
+	}
+	delete(expectedFailedFields, "CreatedBy")
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test65{
+		test65Base:  test65Base{Title: "Title"},
+		test65Audit: &test65Audit{CreatedBy: "john"},
+		Name:        "John",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{ValidateEmbedded: true}, t)
+}
+
+func TestLen(t *testing.T) {
+	s := Test50{
+		Country: "PL",
+		Pair:    []string{"a", "b"},
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test50{
+		Country: "P",
+		Pair:    []string{"a"},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Country": FailLen,
+		"Pair":    FailLen,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test50{
+		Country: "POL",
+		Pair:    []string{"a", "b", "c"},
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.Country = "pl"
+	expectedFailedFields["Country"] = FailRegexp
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestInterfaceFields(t *testing.T) {
+	name := "Johnny"
+	s := Test51{
+		Name:  &name,
+		Age:   30,
+		Other: struct{}{},
+		Size:  "abcd",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test51{
+		Name: "John",
+		Age:  12,
+		Size: "ab",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name": FailLenMin,
+		"Age":  FailValMin,
+		"Size": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	// min and max are resolved against the value that interface holds
+	s = Test51{
+		Name: "Johnny",
+		Size: 10,
+	}
+	expectedFailedFields = map[string]int{
+		"Size": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+	_, _, err := ValidateStrict(&s, &ValidationOptions{StrictTags: true})
+	if err != nil {
+		t.Fatalf("ValidateStrict returned error %v for min and max on interface", err)
+	}
+
+	s = Test51{}
+	expectedFailedFields = map[string]int{
+		"Name": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestValidateSlice(t *testing.T) {
+	s := []Test50{
+		{Country: "P", Pair: []string{"a", "b"}},
+		{Country: "PL", Pair: []string{"a", "b"}},
+		{Country: "PL", Pair: []string{"a"}},
+	}
+	expectedFailedFields := map[int]map[string]int{
+		0: {"Country": FailLen},
+		2: {"Pair": FailLen},
+	}
+	for _, objs := range []interface{}{s, &s, []*Test50{&s[0], &s[1], nil, &s[2]}} {
+		valid, failed := ValidateSlice(objs, &ValidationOptions{})
+		if valid {
+			t.Fatalf("ValidateSlice returned invalid boolean value")
+		}
+		if len(failed) != len(expectedFailedFields) {
+			t.Fatalf("ValidateSlice returned invalid number of failed elements %d where it should be %d", len(failed), len(expectedFailedFields))
+		}
+	}
+	_, failed := ValidateSlice(s, &ValidationOptions{})
+	for j, expected := range expectedFailedFields {
+		compareFailedFields(failed[j], expected, t)
+	}
+
+	valid, failed := ValidateSlice(s[1:2], &ValidationOptions{})
+	if !valid || len(failed) != 0 {
+		t.Fatalf("ValidateSlice returned invalid result for valid slice")
+	}
+
+	valid, failed = ValidateSlice(&s[0], &ValidationOptions{})
+	if valid || failed != nil {
+		t.Fatalf("ValidateSlice returned invalid result for value that is not a slice")
+	}
+}
+
+func TestOmitEmpty(t *testing.T) {
+	s := Test52{}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+	compare(&s, true, map[string]int{}, &ValidationOptions{RequiredByDefault: true}, t)
+
+	s = Test52{
+		Nickname: "Joe",
+		Age:      12,
+		Rate:     -1,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Nickname": FailLenMin | FailRegexp,
+		"Age":      FailValMin,
+		"Rate":     FailPositive,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test52{
+		Nickname: "johnny",
+		Age:      30,
+		Rate:     1.5,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+}
+
+func TestIPAndCIDR(t *testing.T) {
+	s := Test53{
+		Address: "2001:db8::1",
+		Gateway: "192.168.1.1",
+		DNS:     "2001:4860:4860::8888",
+		Subnet:  "10.0.0.0/8",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test53{
+		Address: "192.168.1.300",
+		Gateway: "2001:db8::1",
+		DNS:     "8.8.8.8",
+		Subnet:  "10.0.0.0",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Address": FailIP,
+		"Gateway": FailIP,
+		"DNS":     FailIP,
+		"Subnet":  FailCIDR,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test53{}
+	expectedFailedFields = map[string]int{
+		"Subnet": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestValidateSafe(t *testing.T) {
+	var nilStruct *Test1
+	i := 5
+	for _, obj := range []interface{}{nil, nilStruct, 5, &i, Test1{}} {
+		valid, failedFields := Validate(obj, &ValidationOptions{})
+		if valid || failedFields == nil || len(failedFields) != 0 {
+			t.Fatalf("Validate returned invalid result for %#v", obj)
+		}
+		valid, failedFields, err := ValidateSafe(obj, &ValidationOptions{})
+		if valid || len(failedFields) != 0 || err == nil {
+			t.Fatalf("ValidateSafe returned invalid result for %#v", obj)
+		}
+		if valid, _ := ValidateField(obj, "FirstName", nil); valid {
+			t.Fatalf("ValidateField returned invalid boolean value for %#v", obj)
+		}
+	}
+
+	s := Test50{Country: "PL", Pair: []string{"a", "b"}}
+	valid, _, err := ValidateSafe(&s, nil)
+	if !valid || err != nil {
+		t.Fatalf("ValidateSafe returned invalid result for valid struct")
+	}
+}
+
+func TestStartsWithEndsWith(t *testing.T) {
+	s := Test54{
+		UserID:   "usr_123",
+		Filename: "report.csv",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test54{
+		UserID:   "acc_123",
+		Filename: "report.csv.gz",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"UserID":   FailStartsWith,
+		"Filename": FailEndsWith,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test54{}
+	expectedFailedFields = map[string]int{
+		"Filename": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestPassword(t *testing.T) {
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Password": FailPassword,
+	}
+	for _, password := range []string{"Secret1!", "Zażółć9#"} {
+		s := Test55{Password: password}
+		compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+	}
+	for _, password := range []string{"Secr1!", "secret12!", "Secretly!", "Secret123"} {
+		s := Test55{Password: password}
+		compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+	}
+
+	opts := &ValidationOptions{
+		PasswordPolicy: &PasswordPolicy{
+			MinLen:       12,
+			RequireDigit: true,
+		},
+	}
+	s := Test55{Password: "correcthorse7"}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s = Test55{Password: "Secret1!"}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test55{Password: "correcthorsebattery"}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test55{}
+	expectedFailedFields["Password"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestByteSlice(t *testing.T) {
+	s := Test56{
+		Token:   []byte("abc123"),
+		Contact: []byte("john@example.com"),
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test56{
+		Token:   []byte("ab!"),
+		Contact: []byte("john"),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Token":   FailLenMin | FailRegexp,
+		"Contact": FailEmail,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test56{
+		Token:   []byte{},
+		Contact: []byte("john@example.com"),
+	}
+	expectedFailedFields = map[string]int{
+		"Token": FailEmpty | FailLenMin | FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestDateTime(t *testing.T) {
+	s := Test57{
+		Birthday:  "1990-05-17",
+		CreatedAt: "2021-03-01T13:45",
+		UpdatedAt: "Mon, 01 Mar 2021 13:45:00 UTC",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test57{
+		Birthday:  "17/05/1990",
+		CreatedAt: "2021-03-01",
+		UpdatedAt: "2021-03-01T13:45:00Z",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Birthday":  FailDateTime,
+		"CreatedAt": FailDateTime,
+		"UpdatedAt": FailDateTime,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test57{Birthday: "1990-02-30"}
+	expectedFailedFields = map[string]int{
+		"Birthday":  FailDateTime,
+		"CreatedAt": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestCoerceNumbers(t *testing.T) {
+	opts := &ValidationOptions{CoerceNumbers: true}
+	s := Test58{
+		Age:      "42",
+		Quantity: json.Number("2.5"),
+		Note:     "abc",
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test58{
+		Age:      "12",
+		Quantity: json.Number("-1"),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Age":      FailValMin,
+		"Quantity": FailPositive,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedFailedFields = map[string]int{
+		"Quantity": FailPositive,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test58{
+		Age:      "forty",
+		Quantity: json.Number("many"),
+		Note:     "forty",
+	}
+	expectedFailedFields = map[string]int{
+		"Age":      FailNotNumeric,
+		"Quantity": FailNotNumeric,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// fractional numbers are checked against bounds and sets as well
+	s = Test58{
+		Age:      "17.5",
+		Quantity: json.Number("100.5"),
+		Size:     "38.5",
+	}
+	expectedFailedFields = map[string]int{
+		"Age":      FailValMin,
+		"Quantity": FailLte,
+		"Size":     FailValIn,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test58{
+		Age:      "150.5",
+		Quantity: json.Number("99.5"),
+		Size:     "38.0",
+	}
+	expectedFailedFields = map[string]int{
+		"Age": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test58{}
+	expectedFailedFields = map[string]int{
+		"Age": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldWildcards(t *testing.T) {
+	s := Test59{
+		SecondaryEmail: "john",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"PrimaryEmail":   FailEmpty | FailEmail,
+		"SecondaryEmail": FailEmail,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"*Email": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.RestrictFields["Name"] = true
+	expectedFailedFields["Name"] = FailEmpty
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedFailedFields = map[string]int{
+		"AddressStreet": FailEmpty,
+		"AddressCity":   FailEmpty,
+	}
+	opts = &ValidationOptions{
+		SkipFields: map[string]bool{
+			"*Email": true,
+			"Name":   true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts = &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Address*": true,
+		},
+		SkipFields: map[string]bool{
+			"*City": true,
+		},
+	}
+	expectedFailedFields = map[string]int{
+		"AddressStreet": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts = &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"*Email": false,
+			"Name":   true,
+		},
+	}
+	expectedFailedFields = map[string]int{
+		"Name": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestApplyDefaults(t *testing.T) {
+	s := Test60{}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Country":  FailEmpty | FailLen,
+		"Currency": FailEmpty,
+		"Limit":    FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+	if s.Country != "" || s.Currency != nil || s.Limit != 0 || s.Retries != 0 {
+		t.Fatalf("Validate set defaults without ApplyDefaults")
+	}
+
+	opts := &ValidationOptions{ApplyDefaults: true}
+	expectedFailedFields = map[string]int{
+		"Retries": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+	if s.Country != "PL" || s.Currency == nil || *s.Currency != "PLN" || s.Limit != 10 || s.Retries != 7 {
+		t.Fatalf("Validate did not set defaults: %+v", s)
+	}
+
+	currency := "EUR"
+	s = Test60{
+		Country:  "GB",
+		Currency: &currency,
+		Limit:    200,
+		Retries:  3,
+	}
+	expectedFailedFields = map[string]int{
+		"Limit": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+	if s.Country != "GB" || *s.Currency != "EUR" || s.Limit != 200 || s.Retries != 3 {
+		t.Fatalf("Validate overwrote non-zero fields with defaults: %+v", s)
+	}
+}
+
+func TestDurationBounds(t *testing.T) {
+	interval := time.Minute
+	s := Test61{
+		Timeout:  30 * time.Second,
+		Interval: &interval,
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s.Timeout = time.Hour
+	interval = 100 * time.Millisecond
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s.Timeout = 4 * time.Second
+	interval = 3 * time.Minute
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Timeout":  FailValMin,
+		"Interval": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s.Timeout = 2 * time.Hour
+	interval = time.Millisecond
+	expectedFailedFields = map[string]int{
+		"Timeout":  FailValMax,
+		"Interval": FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestValidateBatch(t *testing.T) {
+	objs := []interface{}{}
+	expectedFailedFields := []map[string]int{}
+	for i := 0; i < 100; i++ {
+		s := &Test50{Country: "PL", Pair: []string{"a", "b"}}
+		expected := map[string]int{}
+		if i%3 == 0 {
+			s.Country = strings.Repeat("P", i%7+1)
+			if i%7 != 1 {
+				expected["Country"] = FailLen
+			}
+		}
+		if i%5 == 0 {
+			s.Pair = s.Pair[:1]
+			expected["Pair"] = FailLen
+		}
+		objs = append(objs, s)
+		expectedFailedFields = append(expectedFailedFields, expected)
+	}
+	objs = append(objs, nil, Test50{})
+	expectedFailedFields = append(expectedFailedFields, map[string]int{}, map[string]int{})
+
+	for _, workers := range []int{0, 1, 8, 200} {
+		results := ValidateBatch(objs, &ValidationOptions{}, workers)
+		if len(results) != len(objs) {
+			t.Fatalf("ValidateBatch returned %d results where it should be %d", len(results), len(objs))
+		}
+		for j, result := range results {
+			compareFailedFields(result.Failed(), expectedFailedFields[j], t)
+			// objects that are not pointers to struct are invalid input
+			invalidInput := j >= len(objs)-2
+			if result.IsValid() != (len(expectedFailedFields[j]) == 0 && !invalidInput) || (result.Err() != nil) != invalidInput {
+				t.Fatalf("ValidateBatch returned invalid result %v, %v for object %d", result.IsValid(), result.Err(), j)
+			}
+		}
+	}
+}
+
+func benchmarkValidateBatch(b *testing.B, workers int) {
+	objs := make([]interface{}, 1000)
+	for j := range objs {
+		objs[j] = &Test1{
+			FirstName: "Johnny",
+			LastName:  "Smith",
+			Age:       35,
+			Price:     100,
+			PostCode:  "00-123",
+			Email:     "john@example.com",
+			BelowZero: -4,
+			Country:   "PL",
+		}
+	}
+	opts := &ValidationOptions{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ValidateBatch(objs, opts, workers)
+	}
+}
+
+func BenchmarkValidateBatch1(b *testing.B) {
+	benchmarkValidateBatch(b, 1)
+}
+
+func BenchmarkValidateBatch8(b *testing.B) {
+	benchmarkValidateBatch(b, 8)
+}
+
+func TestTagSeparators(t *testing.T) {
+	opts := &ValidationOptions{
+		TokenSeparator: ";",
+		KVSeparator:    "=",
+	}
+	s := Test62{
+		FirstName: "Johnny",
+		Title:     "Dr. Who",
+		Tags:      []string{"abc", "def"},
+		Agree:     true,
+	}
+	compare(&s, true, map[string]int{}, opts, t)
+
+	s = Test62{
+		FirstName: "John",
+		Title:     "Dr.Who",
+		Tags:      []string{"abc", "de", "fgh"},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"FirstName": FailLenMin,
+		"Title":     FailStartsWith,
+		"Tags":      FailLenMax | FailElem,
+		"Agree":     FailBool,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s1 := Test1{FirstName: "John", LastName: "S", Age: 12}
+	_, expectedFailedFields = Validate(&s1, &ValidationOptions{})
+	opts.OverwriteFieldTags = map[string]map[string]string{}
+	for _, field := range []string{"FirstName", "LastName", "Age", "Price", "PostCode", "Email", "BelowZero", "DiscountPrice", "County"} {
+		f, _ := reflect.TypeOf(s1).FieldByName(field)
+		tag := strings.Replace(strings.Replace(f.Tag.Get("validation"), ":", "=", -1), " ", ";", -1)
+		opts.OverwriteFieldTags[field] = map[string]string{"validation": tag}
+	}
+	compare(&s1, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestHexColorBase64Hex(t *testing.T) {
+	s := Test63{
+		Background: "#aabbcc",
+		Foreground: "#ABC",
+		Avatar:     "aGVsbG8gd29ybGQ=",
+		Checksum:   "0x1F2e3D",
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test63{Foreground: "#abc"}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test63{
+		Background: "#abcd",
+		Foreground: "red",
+		Avatar:     "aGVsbG8gd29ybGQ",
+		Checksum:   "1g",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Background": FailHexColor,
+		"Foreground": FailHexColor,
+		"Avatar":     FailBase64,
+		"Checksum":   FailHex,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+
+	s = Test63{Background: "aabbcc"}
+	expectedFailedFields = map[string]int{
+		"Background": FailHexColor,
+		"Foreground": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestRecursiveCycle(t *testing.T) {
+	s := &Test64{Name: "first"}
+	s.Next = s
+	opts := &ValidationOptions{Recursive: true}
+	compare(s, true, map[string]int{}, opts, t)
+
+	second := &Test64{Name: "x", Next: s}
+	s.Next = second
+	s.Name = ""
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"Name":      FailEmpty | FailLenMin,
+		"Next.Name": FailLenMin,
+	}
+	compare(s, expectedBool, expectedFailedFields, opts, t)
+
+	// structs in a chain without a cycle are all validated
+	s = &Test64{Name: "first", Next: &Test64{Name: "second", Next: &Test64{Name: "y"}}}
+	expectedFailedFields = map[string]int{
+		"Next.Next.Name": FailLenMin,
+	}
+	compare(s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTimeBoundsUnexported(t *testing.T) {
+	s := Test66{
+		createdAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		meetingAt: time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC),
+	}
+	compare(&s, true, map[string]int{}, &ValidationOptions{}, t)
+
+	s = Test66{
+		createdAt: time.Date(2019, 12, 31, 23, 59, 0, 0, time.UTC),
+		meetingAt: time.Date(2024, 5, 18, 10, 0, 0, 0, time.UTC),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int{
+		"createdAt": FailAfter,
+		"meetingAt": FailWeekday,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
 func compare(s interface{}, expectedBool bool, expectedFailedFields map[string]int, options *ValidationOptions, t *testing.T) {
 	valid, failedFields := Validate(s, options)
 	if valid != expectedBool {